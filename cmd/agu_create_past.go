@@ -14,6 +14,10 @@ func NewAguCreatePastCmd(api *ftapi.APIInterface) *cobra.Command {
 		Use:   "create_past login duration",
 		Short: "List a user's AGUs",
 		Long: "Add a free AGU in the past for a user to delay the blackhole, duration must be in days",
+		Annotations: map[string]string{
+			argsAnnotation: "login",
+		},
+		ValidArgsFunction: completeArgs(api),
 		Args: func(cmd *cobra.Command, args []string) error {
 			n := 2
 			if len(args) != n {
@@ -30,15 +34,19 @@ func NewAguCreatePastCmd(api *ftapi.APIInterface) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			login, err := resolveLogin(cmd.Context(), *api, args[0])
+			if err != nil {
+				return err
+			}
 			reason, err := cmd.Flags().GetString("reason")
 			if err != nil {
 				return err
 			}
-			err = (*api).CreateFreePastAgu(args[0], int(duration), reason)
+			err = (*api).CreateFreePastAguContext(cmd.Context(), login, int(duration), reason)
 			if err != nil {
 				return err
 			}
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "A free past AGU was created of %d days for %s", duration, args[0])
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "A free past AGU was created of %d days for %s", duration, login)
 			return nil
 		},
 	}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"github.com/spf13/cobra"
 	"goft/pkg/ftapi"
+	"io"
+	"strconv"
 )
 
 // NewAguListCmd Create the agu list cmd
@@ -11,9 +13,17 @@ func NewAguListCmd(api *ftapi.APIInterface) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "list login",
 		Short: "List a user's AGUs",
+		Annotations: map[string]string{
+			argsAnnotation: "login",
+		},
+		ValidArgsFunction: completeArgs(api),
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			agus, err := (*api).GetUserAgus(args[0])
+			login, err := resolveLogin(cmd.Context(), *api, args[0])
+			if err != nil {
+				return err
+			}
+			agus, err := (*api).GetUserAgusContext(cmd.Context(), login)
 			if err != nil {
 				return err
 			}
@@ -25,6 +35,7 @@ func NewAguListCmd(api *ftapi.APIInterface) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			shown := make([]ftapi.Agu, 0, len(agus))
 			for _, agu := range agus {
 				if hideFree && agu.IsFree {
 					continue
@@ -32,16 +43,32 @@ func NewAguListCmd(api *ftapi.APIInterface) *cobra.Command {
 				if onlyFree && !agu.IsFree {
 					continue
 				}
-				_, _ = fmt.Fprint(cmd.OutOrStdout(), "\n")
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "ID: %d\n", agu.ID)
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Reason: %s\n", agu.Reason)
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Is free: %t\n", agu.IsFree)
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Begins at: %s\n", agu.BeginDate)
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Expected end: %s\n", agu.ExpectedEndDate)
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Ends at: %s\n", agu.EndDate)
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Created at: %s\n", agu.CreatedAt)
+				shown = append(shown, agu)
+			}
+			t := table{Header: []string{"ID", "REASON", "FREE", "BEGINS AT", "EXPECTED END", "ENDS AT", "CREATED AT"}}
+			for _, agu := range shown {
+				createdAt := ""
+				if agu.CreatedAt != nil {
+					createdAt = agu.CreatedAt.String()
+				}
+				t.Rows = append(t.Rows, []string{strconv.Itoa(agu.ID), agu.Reason, strconv.FormatBool(agu.IsFree), agu.BeginDate, agu.ExpectedEndDate, agu.EndDate, createdAt})
 			}
-			return nil
+			return printOutput(cmd, output{
+				Data:  shown,
+				Table: t,
+				Text: func(w io.Writer) {
+					for _, agu := range shown {
+						_, _ = fmt.Fprint(w, "\n")
+						_, _ = fmt.Fprintf(w, "ID: %d\n", agu.ID)
+						_, _ = fmt.Fprintf(w, "Reason: %s\n", agu.Reason)
+						_, _ = fmt.Fprintf(w, "Is free: %t\n", agu.IsFree)
+						_, _ = fmt.Fprintf(w, "Begins at: %s\n", agu.BeginDate)
+						_, _ = fmt.Fprintf(w, "Expected end: %s\n", agu.ExpectedEndDate)
+						_, _ = fmt.Fprintf(w, "Ends at: %s\n", agu.EndDate)
+						_, _ = fmt.Fprintf(w, "Created at: %s\n", agu.CreatedAt)
+					}
+				},
+			})
 		},
 	}
 	cmd.Flags().Bool("no-free", false, "Don't show free AGUs")
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewAuditCmd creates the audit cmd
+func NewAuditCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "audit",
+		Short: "Query the local log of the changes made through goft",
+		Long: `Every request modifying data on the intra is recorded in a JSON lines file,
+~/.config/goft/audit.jsonl unless audit_log is set in the config file.
+Each entry holds the time, profile, operator, endpoint, payload with passwords redacted and response status.`,
+	}
+}
+
+var auditCmd = NewAuditCmd()
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+}
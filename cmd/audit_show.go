@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"goft/pkg/ftapi"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// parseSince parses the --since flag, either a duration before now like 24h or 7d, or a date
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if strings.HasSuffix(value, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if duration, err := time.ParseDuration(value); err == nil {
+		return now.Add(-duration), nil
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02"} {
+		if since, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return since, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid since '%s', must be a duration like 24h or 7d, or a date like 2006-01-02", value)
+}
+
+// auditEntryTargets returns true if the endpoint of entry is about the user login, like /v2/users/login/closes
+func auditEntryTargets(entry ftapi.AuditEntry, login string) bool {
+	segments := strings.Split(strings.Trim(entry.Endpoint, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "users" && segments[i+1] == login {
+			return true
+		}
+	}
+	return false
+}
+
+// filterAuditEntries returns the entries written after since, about login and sent by operator, the empty values match everything
+func filterAuditEntries(entries []ftapi.AuditEntry, since time.Time, login string, operator string) []ftapi.AuditEntry {
+	filtered := make([]ftapi.AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Time.Before(since) {
+			continue
+		}
+		if login != "" && !auditEntryTargets(entry, login) {
+			continue
+		}
+		if operator != "" && entry.Operator != operator {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	return filtered
+}
+
+// auditEntryStatus is the status or error of the request in a single word, for the table of entries
+func auditEntryStatus(entry ftapi.AuditEntry) string {
+	if entry.Error != "" {
+		return "error"
+	}
+	return strconv.Itoa(entry.Status)
+}
+
+// NewAuditShowCmd creates the audit show cmd
+func NewAuditShowCmd(log **ftapi.AuditLog) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show the changes made through goft",
+		Annotations: map[string]string{
+			credentialsAnnotation: "none",
+		},
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var since time.Time
+			if value, _ := cmd.Flags().GetString("since"); value != "" {
+				var err error
+				since, err = parseSince(value, time.Now())
+				if err != nil {
+					return err
+				}
+			}
+			login, _ := cmd.Flags().GetString("login")
+			operator, _ := cmd.Flags().GetString("operator")
+			entries, err := (*log).Read()
+			if err != nil {
+				return err
+			}
+			entries = filterAuditEntries(entries, since, login, operator)
+
+			t := table{Header: []string{"TIME", "PROFILE", "OPERATOR", "METHOD", "ENDPOINT", "STATUS"}}
+			for _, entry := range entries {
+				t.Rows = append(t.Rows, []string{
+					entry.Time.Local().Format("2006-01-02 15:04:05"),
+					entry.Profile,
+					entry.Operator,
+					entry.Method,
+					entry.Endpoint,
+					auditEntryStatus(entry),
+				})
+			}
+			return printOutput(cmd, output{
+				Data:  entries,
+				Table: t,
+				Text: func(w io.Writer) {
+					for _, entry := range entries {
+						_, _ = fmt.Fprintf(w, "%s %s %s %s %s\n", entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Operator, entry.Method, entry.Endpoint, auditEntryStatus(entry))
+						if len(entry.Payload) > 0 {
+							_, _ = fmt.Fprintf(w, "    %s\n", entry.Payload)
+						}
+						if entry.Error != "" {
+							_, _ = fmt.Fprintf(w, "    %s\n", entry.Error)
+						}
+					}
+				},
+			})
+		},
+	}
+	cmd.Flags().String("since", "", "Only show the changes made since a duration like 24h or 7d, or a date like 2006-01-02")
+	cmd.Flags().String("login", "", "Only show the changes about this user")
+	cmd.Flags().String("operator", "", "Only show the changes made by this operator")
+	return cmd
+}
+
+var auditShowCmd = NewAuditShowCmd(&auditLog)
+
+func init() {
+	auditCmd.AddCommand(auditShowCmd)
+}
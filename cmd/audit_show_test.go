@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"goft/pkg/ftapi"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2021, 3, 10, 12, 0, 0, 0, time.Local)
+	for value, expected := range map[string]time.Time{
+		"24h":              now.Add(-24 * time.Hour),
+		"7d":               now.AddDate(0, 0, -7),
+		"2021-03-01":       time.Date(2021, 3, 1, 0, 0, 0, 0, time.Local),
+		"2021-03-01 08:30": time.Date(2021, 3, 1, 8, 30, 0, 0, time.Local),
+	} {
+		since, err := parseSince(value, now)
+		assert.Nil(t, err, value)
+		assert.True(t, expected.Equal(since), value)
+	}
+	_, err := parseSince("yesterday", now)
+	assert.EqualError(t, err, "invalid since 'yesterday', must be a duration like 24h or 7d, or a date like 2006-01-02")
+}
+
+func TestAuditShow(t *testing.T) {
+	log := ftapi.NewAuditLog(filepath.Join(t.TempDir(), "audit.jsonl"), "", nil)
+	now := time.Now()
+	for _, entry := range []ftapi.AuditEntry{
+		{Time: now.Add(-72 * time.Hour), Operator: "mbounya", Method: "PATCH", Endpoint: "/v2/users/spoody", Status: 204},
+		{Time: now.Add(-time.Hour), Operator: "mbounya", Method: "POST", Endpoint: "/v2/users/spoody/correction_points/add", Status: 201},
+		{Time: now.Add(-time.Hour), Operator: "other", Method: "POST", Endpoint: "/v2/users/other/closes", Error: "connection reset"},
+	} {
+		assert.Nil(t, log.Append(entry))
+	}
+
+	stdout := bytes.NewBufferString("")
+	showCmd := NewAuditShowCmd(&log)
+	showCmd.Flags().StringP("output", "o", "", outputFlagUsage)
+	showCmd.SetArgs([]string{"--since", "1d", "--login", "spoody", "-o", "json"})
+	showCmd.SetOut(stdout)
+	assert.Nil(t, showCmd.Execute())
+	var entries []ftapi.AuditEntry
+	assert.Nil(t, json.Unmarshal(stdout.Bytes(), &entries))
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "/v2/users/spoody/correction_points/add", entries[0].Endpoint)
+
+	stdout.Reset()
+	showCmd = NewAuditShowCmd(&log)
+	showCmd.SetArgs([]string{"--operator", "other"})
+	showCmd.SetOut(stdout)
+	assert.Nil(t, showCmd.Execute())
+	assert.Equal(t, now.Add(-time.Hour).Local().Format("2006-01-02 15:04:05")+" other POST /v2/users/other/closes error\n    connection reset\n", stdout.String())
+}
@@ -0,0 +1,30 @@
+package cmd
+
+import (
+	"goft/pkg/ftapi"
+
+	"github.com/spf13/cobra"
+)
+
+// NewAuthCmd creates auth cmd
+func NewAuthCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "auth",
+		Short: "Manage the access tokens used to talk to the API",
+	}
+}
+
+// selectTokens returns the user tokens with --token user, the application's otherwise
+func selectTokens(cmd *cobra.Command, appTokens *ftapi.CachedTokenSource, userTokens *ftapi.CachedTokenSource) *ftapi.CachedTokenSource {
+	token, _ := cmd.Flags().GetString("token")
+	if token == "user" {
+		return userTokens
+	}
+	return appTokens
+}
+
+var authCmd = NewAuthCmd()
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+}
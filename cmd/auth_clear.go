@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"goft/pkg/ftapi"
+
+	"github.com/spf13/cobra"
+)
+
+// NewAuthClearCmd create the auth clear cmd
+func NewAuthClearCmd(appTokens **ftapi.CachedTokenSource, userTokens **ftapi.CachedTokenSource) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Remove the cached access token",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tokens := selectTokens(cmd, *appTokens, *userTokens)
+			err := tokens.Clear()
+			if err != nil {
+				return err
+			}
+			cmd.Println("Cached token removed")
+			return nil
+		},
+	}
+	return cmd
+}
+
+var authClearCmd = NewAuthClearCmd(&appTokens, &userTokens)
+
+func init() {
+	authCmd.AddCommand(authClearCmd)
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"goft/pkg/ftapi"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/oauth2"
+)
+
+// loginTimeout is how long we wait for the user to authorize goft in the browser
+const loginTimeout = 5 * time.Minute
+
+// NewAuthLoginCmd create the auth login cmd
+func NewAuthLoginCmd(config **oauth2.Config, tokens **ftapi.CachedTokenSource) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in as yourself using the browser",
+		Long: `Log in with your intra account using the OAuth authorization code flow.
+
+The redirect_uri set in the config file (defaults to http://localhost:4242/callback)
+must be one of the redirect URIs of your application. goft listens on it to receive
+the authorization code, if it can't, or with --no-browser, you have to paste the code
+or the URL you were redirected to.
+
+Once logged in, commands acting on your behalf like repo list use your token,
+use --token app or --token user to choose explicitly.`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			noBrowser, err := cmd.Flags().GetBool("no-browser")
+			if err != nil {
+				return err
+			}
+			// Resolved before opening the browser, so a credential helper doesn't prompt after authorizing
+			if (*config).ClientSecret == "" {
+				(*config).ClientSecret, err = clientSecret()
+				if err != nil {
+					return err
+				}
+			}
+			state, err := randomState()
+			if err != nil {
+				return err
+			}
+			authURL := (*config).AuthCodeURL(state)
+
+			var code string
+			if !noBrowser {
+				code, err = waitForCode(cmd, (*config).RedirectURL, authURL, state)
+				if errors.Is(err, errListenFailed) {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Couldn't listen on %s, falling back to manual mode\n", (*config).RedirectURL)
+					noBrowser = true
+				} else if errors.Is(err, errBrowserFailed) {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "%s, falling back to manual mode\n", err)
+					noBrowser = true
+				} else if err != nil {
+					return err
+				}
+			}
+			if noBrowser {
+				code, err = promptForCode(cmd, authURL, state)
+				if err != nil {
+					return err
+				}
+			}
+
+			token, err := (*config).Exchange(cmd.Context(), code)
+			if err != nil {
+				return err
+			}
+			err = (*tokens).Store(token)
+			if err != nil {
+				return err
+			}
+			cmd.Println("Logged in")
+			return nil
+		},
+	}
+	cmd.Flags().Bool("no-browser", false, "Don't listen for the redirect, paste the authorization code instead")
+	return cmd
+}
+
+var errListenFailed = errors.New("failed listening for the redirect")
+
+var errBrowserFailed = errors.New("couldn't open the browser")
+
+// openBrowser opens the authorization URL, tests replace it to simulate a missing browser
+var openBrowser = execBrowser
+
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// parseRedirectURL returns the address and path to listen on for redirectURL, the path defaults to /
+func parseRedirectURL(redirectURL string) (string, string, error) {
+	redirect, err := url.Parse(redirectURL)
+	if err != nil || redirect.Scheme != "http" || redirect.Host == "" {
+		return "", "", fmt.Errorf("invalid redirect_uri '%s', must be like http://localhost:4242/callback", redirectURL)
+	}
+	path := redirect.Path
+	if path == "" {
+		path = "/"
+	}
+	return redirect.Host, path, nil
+}
+
+// waitForCode opens authURL in the browser and serves redirectURL until the intra redirects to it with a code
+func waitForCode(cmd *cobra.Command, redirectURL string, authURL string, state string) (string, error) {
+	host, path, err := parseRedirectURL(redirectURL)
+	if err != nil {
+		return "", err
+	}
+	listener, err := net.Listen("tcp", host)
+	if err != nil {
+		return "", errListenFailed
+	}
+	type result struct {
+		code string
+		err  error
+	}
+	results := make(chan result, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(rw http.ResponseWriter, req *http.Request) {
+		code, err := codeFromQuery(req.URL.Query(), state)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+		} else {
+			_, _ = io.WriteString(rw, "goft is now authorized, you can close this window.\n")
+		}
+		select {
+		case results <- result{code, err}:
+		default:
+		}
+	})
+	server := &http.Server{Handler: mux}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Close()
+
+	// The browser may start without being able to display anything, like xdg-open over SSH
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Opening the browser to authorize goft, if it doesn't open visit:\n%s\n", authURL)
+	err = openBrowser(authURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", errBrowserFailed, err)
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), loginTimeout)
+	defer cancel()
+	select {
+	case res := <-results:
+		return res.code, res.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// promptForCode asks the user to paste either the code or the whole URL they were redirected to
+func promptForCode(cmd *cobra.Command, authURL string, state string) (string, error) {
+	_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Open this URL in your browser and authorize goft:\n%s\n\n", authURL)
+	_, _ = fmt.Fprint(cmd.OutOrStdout(), "Paste the code or the URL you were redirected to: ")
+	input, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && input == "" {
+		return "", err
+	}
+	input = strings.TrimSpace(input)
+	if !strings.Contains(input, "code=") {
+		if input == "" {
+			return "", errors.New("no code provided")
+		}
+		return input, nil
+	}
+	redirected, err := url.Parse(input)
+	if err != nil {
+		return "", err
+	}
+	return codeFromQuery(redirected.Query(), state)
+}
+
+func codeFromQuery(query url.Values, state string) (string, error) {
+	if errMsg := query.Get("error"); errMsg != "" {
+		return "", fmt.Errorf("authorization failed: %s %s", errMsg, query.Get("error_description"))
+	}
+	if query.Get("state") != state {
+		return "", errors.New("invalid state, please try again")
+	}
+	code := query.Get("code")
+	if code == "" {
+		return "", errors.New("no code provided")
+	}
+	return code, nil
+}
+
+var authLoginCmd = NewAuthLoginCmd(&userOAuthConfig, &userTokens)
+
+func init() {
+	authCmd.AddCommand(authLoginCmd)
+}
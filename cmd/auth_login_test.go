@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"goft/pkg/ftapi"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+func TestAuthLoginWithPastedCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Nil(t, req.ParseForm())
+		assert.Equal(t, "authorization_code", req.PostForm.Get("grant_type"))
+		assert.Equal(t, "thecode", req.PostForm.Get("code"))
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"access_token":"user","refresh_token":"refresh","token_type":"bearer","expires_in":7200}`))
+	}))
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "goft")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	config := &oauth2.Config{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		Endpoint:     oauth2.Endpoint{AuthURL: server.URL + "/oauth/authorize", TokenURL: server.URL + "/oauth/token", AuthStyle: oauth2.AuthStyleInParams},
+		RedirectURL:  "http://localhost:4242/callback",
+	}
+	cache := ftapi.NewTokenCache(filepath.Join(dir, "user_token.json"))
+	tokens := ftapi.NewCachedTokenSource(cache, nil)
+	stdout := bytes.NewBufferString("")
+	cmd := NewAuthLoginCmd(&config, &tokens)
+	cmd.SetIn(bytes.NewBufferString("thecode\n"))
+	cmd.SetOut(stdout)
+	cmd.SetArgs([]string{"--no-browser"})
+	assert.Nil(t, cmd.Execute())
+	assert.Contains(t, stdout.String(), server.URL+"/oauth/authorize?")
+	assert.Contains(t, stdout.String(), "Logged in")
+
+	token, err := cache.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, "user", token.AccessToken)
+	assert.Equal(t, "refresh", token.RefreshToken)
+}
+
+func TestAuthLoginBrowserFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Nil(t, req.ParseForm())
+		assert.Equal(t, "thecode", req.PostForm.Get("code"))
+		rw.Header().Set("Content-Type", "application/json")
+		_, _ = rw.Write([]byte(`{"access_token":"user","token_type":"bearer","expires_in":7200}`))
+	}))
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "goft")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	defer func(open func(string) error) { openBrowser = open }(openBrowser)
+	openBrowser = func(url string) error {
+		return errors.New("cannot start command")
+	}
+
+	config := &oauth2.Config{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		Endpoint:     oauth2.Endpoint{AuthURL: server.URL + "/oauth/authorize", TokenURL: server.URL + "/oauth/token", AuthStyle: oauth2.AuthStyleInParams},
+		RedirectURL:  "http://127.0.0.1:0/callback",
+	}
+	tokens := ftapi.NewCachedTokenSource(ftapi.NewTokenCache(filepath.Join(dir, "user_token.json")), nil)
+	stdout := bytes.NewBufferString("")
+	stderr := bytes.NewBufferString("")
+	cmd := NewAuthLoginCmd(&config, &tokens)
+	cmd.SetIn(bytes.NewBufferString("thecode\n"))
+	cmd.SetOut(stdout)
+	cmd.SetErr(stderr)
+	cmd.SetArgs([]string{})
+	assert.Nil(t, cmd.Execute())
+	assert.Contains(t, stderr.String(), "if it doesn't open visit:\n"+server.URL+"/oauth/authorize?")
+	assert.Contains(t, stderr.String(), "couldn't open the browser: cannot start command, falling back to manual mode\n")
+	assert.Contains(t, stdout.String(), "Logged in")
+}
+
+func TestCodeFromQuery(t *testing.T) {
+	code, err := codeFromQuery(map[string][]string{"code": {"thecode"}, "state": {"state"}}, "state")
+	assert.Nil(t, err)
+	assert.Equal(t, "thecode", code)
+
+	_, err = codeFromQuery(map[string][]string{"code": {"thecode"}, "state": {"other"}}, "state")
+	assert.NotNil(t, err)
+
+	_, err = codeFromQuery(map[string][]string{"error": {"access_denied"}, "state": {"state"}}, "state")
+	assert.NotNil(t, err)
+}
+
+func TestParseRedirectURL(t *testing.T) {
+	host, path, err := parseRedirectURL("http://localhost:4242/callback")
+	assert.Nil(t, err)
+	assert.Equal(t, "localhost:4242", host)
+	assert.Equal(t, "/callback", path)
+
+	host, path, err = parseRedirectURL("http://localhost:4242")
+	assert.Nil(t, err)
+	assert.Equal(t, "localhost:4242", host)
+	assert.Equal(t, "/", path)
+
+	_, _, err = parseRedirectURL("localhost:4242")
+	assert.EqualError(t, err, "invalid redirect_uri 'localhost:4242', must be like http://localhost:4242/callback")
+	_, _, err = parseRedirectURL("")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"goft/pkg/ftapi"
+
+	"github.com/spf13/cobra"
+)
+
+// NewAuthRefreshCmd create the auth refresh cmd
+func NewAuthRefreshCmd(appTokens **ftapi.CachedTokenSource, userTokens **ftapi.CachedTokenSource) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "refresh",
+		Short: "Fetch a new access token and cache it",
+		Long: `Fetch a new access token even if the cached one is still valid.
+
+Tokens are cached in ~/.config/goft/ and reused until they expire,
+use --token user to refresh the token of the logged in user.`,
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tokens := selectTokens(cmd, *appTokens, *userTokens)
+			token, err := tokens.Refresh()
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Token refreshed, it expires at %s\n", token.Expiry.Local().Format("2006-01-02 15:04:05"))
+			return nil
+		},
+	}
+	return cmd
+}
+
+var authRefreshCmd = NewAuthRefreshCmd(&appTokens, &userTokens)
+
+func init() {
+	authCmd.AddCommand(authRefreshCmd)
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewBlackholeCmd creates the blackhole cmd
+func NewBlackholeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "blackhole",
+		Short: "Follow the students approaching their blackhole",
+	}
+}
+
+var blackholeCmd = NewBlackholeCmd()
+
+func init() {
+	rootCmd.AddCommand(blackholeCmd)
+}
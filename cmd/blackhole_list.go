@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"goft/pkg/ftapi"
+	"html"
+	"io"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/gomail.v2"
+)
+
+// blackholeStudent is a student of the blackhole list
+type blackholeStudent struct {
+	Login        string    `json:"login"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email"`
+	Level        float32   `json:"level"`
+	BlackholedAt time.Time `json:"blackholed_at"`
+	// DaysLeft is rounded up, a blackhole in 12 hours is 1 day left
+	DaysLeft int `json:"days_left"`
+	// LastProject is the last validated project, empty when none was
+	LastProject     string     `json:"last_project,omitempty"`
+	LastValidatedAt *time.Time `json:"last_validated_at,omitempty"`
+}
+
+// parseDuration parses a number of days like 14d or a duration like 36h
+func parseDuration(value string) (time.Duration, error) {
+	if strings.HasSuffix(value, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && days >= 0 {
+			return time.Duration(days) * 24 * time.Hour, nil
+		}
+	}
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return duration, nil
+	}
+	return 0, fmt.Errorf("invalid duration '%s', must be a number of days like 14d or a duration like 36h", value)
+}
+
+// lastValidatedProject returns the validated project of login marked last, nil when none was
+func lastValidatedProject(ctx context.Context, api ftapi.APIInterface, login string, cursusID int) (*ftapi.ProjectUser, error) {
+	var projects []*ftapi.ProjectUser
+	err := api.ListUserProjectsContext(ctx, login, map[string]string{"marked": "true"}, nil).PageSize(ftapi.MaxPageSize).All(&projects)
+	if err != nil {
+		return nil, err
+	}
+	var last *ftapi.ProjectUser
+	for _, project := range projects {
+		if !project.Validated || project.MarkedAt == nil || !containsInt(project.CursusIDs, cursusID) {
+			continue
+		}
+		if last == nil || project.MarkedAt.After(*last.MarkedAt) {
+			last = project
+		}
+	}
+	return last, nil
+}
+
+// containsInt returns true if values contains value, or if values is empty because the API didn't say
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return len(values) == 0
+}
+
+// listBlackholeStudents returns the active students of the campus whose blackhole is before now+within, the closest first
+func listBlackholeStudents(ctx context.Context, api ftapi.APIInterface, campusID int, cursusID int, now time.Time, within time.Duration) ([]*blackholeStudent, error) {
+	until := now.Add(within)
+	params := url.Values{}
+	params.Set("filter[cursus_id]", strconv.Itoa(cursusID))
+	params.Set("range[blackholed_at]", now.UTC().Format(time.RFC3339)+","+until.UTC().Format(time.RFC3339))
+	pager := api.ListCampusCursusUsersContext(ctx, campusID, params).PageSize(ftapi.MaxPageSize)
+	var students []*blackholeStudent
+	for pager.Next() {
+		var cursusUser ftapi.CursusUser
+		if err := pager.Decode(&cursusUser); err != nil {
+			return nil, err
+		}
+		// The range filter is checked again as the API may ignore it, and the users who left the cursus are skipped
+		if cursusUser.User == nil || cursusUser.BlackholedAt == nil ||
+			cursusUser.BlackholedAt.Before(now) || cursusUser.BlackholedAt.After(until) ||
+			(cursusUser.EndAt != nil && cursusUser.EndAt.Before(now)) {
+			continue
+		}
+		user := cursusUser.User
+		students = append(students, &blackholeStudent{
+			Login:        user.Login,
+			Name:         strings.TrimSpace(user.FirstName + " " + user.LastName),
+			Email:        user.Email,
+			Level:        cursusUser.Level,
+			BlackholedAt: *cursusUser.BlackholedAt,
+			DaysLeft:     int(math.Ceil(cursusUser.BlackholedAt.Sub(now).Hours() / 24)),
+		})
+	}
+	if err := pager.Err(); err != nil {
+		return nil, err
+	}
+	for _, student := range students {
+		project, err := lastValidatedProject(ctx, api, student.Login, cursusID)
+		if err != nil {
+			return nil, err
+		}
+		if project != nil {
+			student.LastProject = project.Project.Slug
+			student.LastValidatedAt = project.MarkedAt
+		}
+	}
+	sort.SliceStable(students, func(i, j int) bool { return students[i].BlackholedAt.Before(students[j].BlackholedAt) })
+	return students, nil
+}
+
+// blackholeTable returns the table and csv view of the blackhole list
+func blackholeTable(students []*blackholeStudent) table {
+	t := table{Header: []string{"LOGIN", "NAME", "EMAIL", "LEVEL", "BLACKHOLED AT", "DAYS LEFT", "LAST VALIDATED PROJECT", "VALIDATED AT"}}
+	for _, student := range students {
+		t.Rows = append(t.Rows, []string{
+			student.Login,
+			student.Name,
+			student.Email,
+			strconv.FormatFloat(float64(student.Level), 'f', 2, 32),
+			student.BlackholedAt.Local().Format("2006-01-02"),
+			strconv.Itoa(student.DaysLeft),
+			student.LastProject,
+			formatDate(student.LastValidatedAt),
+		})
+	}
+	return t
+}
+
+// writeBlackholeText prints the blackhole list aligned in columns
+func writeBlackholeText(w io.Writer, students []*blackholeStudent) {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, student := range students {
+		last := "no validated project"
+		if student.LastProject != "" {
+			last = fmt.Sprintf("%s on %s", student.LastProject, student.LastValidatedAt.Local().Format("2006-01-02"))
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%d days left (%s)\t%s\n", student.Login, student.Name, student.DaysLeft, student.BlackholedAt.Local().Format("2006-01-02"), last)
+	}
+	_ = tw.Flush()
+}
+
+// newBlackholeMail builds the email sending the blackhole list to recipients
+func newBlackholeMail(from string, recipients []string, within string, students []*blackholeStudent) *gomail.Message {
+	m := gomail.NewMessage()
+	m.SetHeader("From", from)
+	m.SetHeader("To", recipients...)
+	m.SetHeader("Subject", fmt.Sprintf("Blackhole watchlist: %d students within %s", len(students), within))
+
+	var txtBody bytes.Buffer
+	_, _ = fmt.Fprintf(&txtBody, "%d students reach their blackhole within %s:\n\n", len(students), within)
+	writeBlackholeText(&txtBody, students)
+
+	var htmlBody bytes.Buffer
+	t := blackholeTable(students)
+	_, _ = fmt.Fprintf(&htmlBody, "<html>\n<b>%d</b> students reach their blackhole within %s:<br />\n<table>\n<tr>", len(students), html.EscapeString(within))
+	for _, column := range t.Header {
+		_, _ = fmt.Fprintf(&htmlBody, "<th>%s</th>", html.EscapeString(column))
+	}
+	_, _ = fmt.Fprint(&htmlBody, "</tr>\n")
+	for _, row := range t.Rows {
+		_, _ = fmt.Fprint(&htmlBody, "<tr>")
+		for _, cell := range row {
+			_, _ = fmt.Fprintf(&htmlBody, "<td>%s</td>", html.EscapeString(cell))
+		}
+		_, _ = fmt.Fprint(&htmlBody, "</tr>\n")
+	}
+	_, _ = fmt.Fprint(&htmlBody, "</table>\n</html>\n")
+
+	m.SetBody("text/html", htmlBody.String())
+	m.AddAlternative("text/plain", txtBody.String())
+	return m
+}
+
+// NewBlackholeListCmd creates the blackhole list cmd
+func NewBlackholeListCmd(api *ftapi.APIInterface, dial smtpDialer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the students whose blackhole is approaching",
+		Long: `List the students of a campus whose blackhole is within the given time, the closest first,
+with their level and last validated project.
+
+The campus defaults to the campus_id of the config file. With --email the list is sent
+to the given addresses instead of being printed, through the SMTP settings of users reset-passwd:
+  goft blackhole list --campus benguerir --within 14d --email tutors@1337.ma`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(0)(cmd, args); err != nil {
+				return err
+			}
+			if err := checkCampusFlag(cmd); err != nil {
+				return err
+			}
+			within, _ := cmd.Flags().GetString("within")
+			if _, err := parseDuration(within); err != nil {
+				return err
+			}
+			if recipients, _ := cmd.Flags().GetStringSlice("email"); len(recipients) > 0 {
+				return readSMTPSettings(cmd).validate()
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			campusID, err := campusFlagID(cmd, *api)
+			if err != nil {
+				return err
+			}
+			cursus, _ := cmd.Flags().GetString("cursus")
+			cursusID, err := resolveCursusID(cmd.Context(), *api, cursus)
+			if err != nil {
+				return err
+			}
+			within, _ := cmd.Flags().GetString("within")
+			duration, _ := parseDuration(within)
+			students, err := listBlackholeStudents(cmd.Context(), *api, campusID, cursusID, time.Now(), duration)
+			if err != nil {
+				return err
+			}
+
+			recipients, _ := cmd.Flags().GetStringSlice("email")
+			if len(recipients) == 0 {
+				return printOutput(cmd, output{
+					Data:  students,
+					Table: blackholeTable(students),
+					Text: func(w io.Writer) {
+						writeBlackholeText(w, students)
+					},
+				})
+			}
+			settings := readSMTPSettings(cmd)
+			out := cmd.OutOrStdout()
+			if isDryRun(cmd) {
+				_, _ = fmt.Fprintf(out, "Would send the blackhole list of %d students to %s through %s:%d\n", len(students), strings.Join(recipients, ", "), settings.Host, settings.Port)
+				return nil
+			}
+			sender, err := dial(settings)
+			if err != nil {
+				return fmt.Errorf("failed connecting to the SMTP server: %w", err)
+			}
+			if err := deliverMail(dial, settings, sender, newBlackholeMail(settings.From, recipients, within, students)); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(out, "The blackhole list of %d students was sent to %s\n", len(students), strings.Join(recipients, ", "))
+			return nil
+		},
+	}
+	addCampusFlag(cmd, api)
+	cmd.Flags().String("cursus", "21", "Cursus ID or slug")
+	cmd.Flags().String("within", "14d", "Only list the blackholes within this time, like 14d or 36h")
+	cmd.Flags().StringSlice("email", nil, "Send the list to these addresses instead of printing it")
+	addSMTPFlags(cmd)
+	return cmd
+}
+
+var blackholeListCmd = NewBlackholeListCmd(&API, dialSMTP)
+
+func init() {
+	blackholeCmd.AddCommand(blackholeListCmd)
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"goft/pkg/ftapi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newBlackholeServer serves 3 students of the campus 21, blackholed in 3, 10 and 40 days from now, and one who left
+func newBlackholeServer(t *testing.T, now time.Time) *httptest.Server {
+	day := func(days int) string {
+		return now.AddDate(0, 0, days).UTC().Format(time.RFC3339)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/cursus_users":
+			assert.Equal(t, "21", req.URL.Query().Get("filter[campus_id]"))
+			assert.Equal(t, "21", req.URL.Query().Get("filter[cursus_id]"))
+			_, _ = fmt.Fprintf(rw, `[
+				{"id":1,"level":3.5,"blackholed_at":%q,"user":{"login":"late","first_name":"Late","last_name":"Student"}},
+				{"id":2,"level":1.2,"blackholed_at":%q,"user":{"login":"urgent","first_name":"Urgent","last_name":"Student","email":"urgent@1337.ma"}},
+				{"id":3,"level":7,"blackholed_at":%q,"user":{"login":"fine"}},
+				{"id":4,"level":2,"blackholed_at":%q,"end_at":%q,"user":{"login":"gone"}}
+			]`, day(10), day(3), day(40), day(5), day(-1))
+		case "/users/urgent/projects_users":
+			_, _ = fmt.Fprintf(rw, `[
+				{"validated?":true,"marked_at":"2021-11-02T10:00:00Z","cursus_ids":[21],"project":{"slug":"libft"}},
+				{"validated?":true,"marked_at":"2021-12-02T10:00:00Z","cursus_ids":[21],"project":{"slug":"get_next_line"}},
+				{"validated?":false,"marked_at":"2022-01-02T10:00:00Z","cursus_ids":[21],"project":{"slug":"ft_printf"}},
+				{"validated?":true,"marked_at":"2022-01-02T10:00:00Z","cursus_ids":[9],"project":{"slug":"c-piscine-shell-00"}}
+			]`)
+		case "/users/late/projects_users":
+			_, _ = rw.Write([]byte(`[]`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestParseDuration(t *testing.T) {
+	duration, err := parseDuration("14d")
+	assert.Nil(t, err)
+	assert.Equal(t, 14*24*time.Hour, duration)
+	duration, err = parseDuration("36h")
+	assert.Nil(t, err)
+	assert.Equal(t, 36*time.Hour, duration)
+	_, err = parseDuration("-2d")
+	assert.EqualError(t, err, "invalid duration '-2d', must be a number of days like 14d or a duration like 36h")
+}
+
+func TestBlackholeList(t *testing.T) {
+	now := time.Now()
+	server := newBlackholeServer(t, now)
+	var api ftapi.APIInterface = ftapi.New(server.URL, server.Client(), ftapi.WithRateLimiter(nil))
+
+	students, err := listBlackholeStudents(context.Background(), api, 21, 21, now, 14*24*time.Hour)
+	assert.Nil(t, err)
+	assert.Len(t, students, 2)
+	assert.Equal(t, "urgent", students[0].Login)
+	assert.Equal(t, "Urgent Student", students[0].Name)
+	assert.Equal(t, 3, students[0].DaysLeft)
+	assert.Equal(t, "get_next_line", students[0].LastProject)
+	assert.Equal(t, "late", students[1].Login)
+	assert.Equal(t, "", students[1].LastProject)
+
+	stdout := bytes.NewBufferString("")
+	listCmd := NewBlackholeListCmd(&api, (&mockSMTP{}).dial)
+	listCmd.Flags().StringP("output", "o", "", outputFlagUsage)
+	listCmd.SetArgs([]string{"--campus", "21", "-o", "csv"})
+	listCmd.SetOut(stdout)
+	assert.Nil(t, listCmd.Execute())
+	assert.True(t, strings.HasPrefix(stdout.String(), "LOGIN,NAME,EMAIL,LEVEL,BLACKHOLED AT,DAYS LEFT,LAST VALIDATED PROJECT,VALIDATED AT\n"))
+	assert.Contains(t, stdout.String(), "\nurgent,Urgent Student,urgent@1337.ma,1.20,")
+}
+
+func TestBlackholeListEmail(t *testing.T) {
+	server := newBlackholeServer(t, time.Now())
+	var api ftapi.APIInterface = ftapi.New(server.URL, server.Client(), ftapi.WithRateLimiter(nil))
+	smtp := &mockSMTP{}
+	stdout := bytes.NewBufferString("")
+	listCmd := NewBlackholeListCmd(&api, smtp.dial)
+	listCmd.SetArgs([]string{"--campus", "21", "--email", "tutors@1337.ma",
+		"--smtp-host", "smtp.test", "--smtp-user", "goft", "--smtp-pass", "pass", "--from-email", "noreply@1337.ma"})
+	listCmd.SetOut(stdout)
+	assert.Nil(t, listCmd.Execute())
+	assert.Equal(t, "The blackhole list of 2 students was sent to tutors@1337.ma\n", stdout.String())
+	assert.Equal(t, 1, smtp.sent)
+
+	listCmd = NewBlackholeListCmd(&api, smtp.dial)
+	listCmd.SetArgs([]string{"--campus", "21", "--email", "tutors@1337.ma"})
+	listCmd.SilenceUsage = true
+	listCmd.SetOut(stdout)
+	listCmd.SetErr(stdout)
+	assert.EqualError(t, listCmd.Execute(), "missing SMTP settings, set them with flags or in the smtp section of the config file")
+}
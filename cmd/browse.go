@@ -15,7 +15,11 @@ func NewBrowseCmd(api *ftapi.APIInterface) *cobra.Command {
 		Use:   "browse <project slug>",
 		Short: "Open the project page in the web browser",
 		Long:  `Open the project page in the web browser`,
-		Args:  cobra.ExactArgs(1),
+		Annotations: map[string]string{
+			argsAnnotation: "project",
+		},
+		ValidArgsFunction: completeArgs(api),
+		Args:              cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			baseURL := "https://projects.intra.42.fr/"
 			url := baseURL + args[0] + "/mine"
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goft/pkg/ftapi"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// resolveCampus finds the campus with the given ID, name or slug, names are matched ignoring case
+func resolveCampus(ctx context.Context, api ftapi.APIInterface, campus string) (*ftapi.Campus, error) {
+	if id, err := strconv.Atoi(campus); err == nil {
+		return api.GetCampusContext(ctx, id)
+	}
+	var campuses []*ftapi.Campus
+	err := api.ListCampusesContext(ctx, nil).PageSize(ftapi.MaxPageSize).All(&campuses)
+	if err != nil {
+		return nil, err
+	}
+	slug := ftapi.Slugify(campus)
+	var found []*ftapi.Campus
+	for _, c := range campuses {
+		if strings.EqualFold(c.Name, campus) || c.Slug() == slug {
+			found = append(found, c)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, fmt.Errorf("campus %s: %w", campus, ftapi.ErrNotFound)
+	case 1:
+		return found[0], nil
+	}
+	ids := make([]string, 0, len(found))
+	for _, c := range found {
+		ids = append(ids, fmt.Sprintf("%s (%d)", c.Name, c.ID))
+	}
+	return nil, fmt.Errorf("campus %s is ambiguous, use the ID of one of %s", campus, strings.Join(ids, ", "))
+}
+
+// resolveCampusID is the same as resolveCampus but only returns the ID, which is not looked up when given
+func resolveCampusID(ctx context.Context, api ftapi.APIInterface, campus string) (int, error) {
+	if id, err := strconv.Atoi(campus); err == nil {
+		return id, nil
+	}
+	found, err := resolveCampus(ctx, api, campus)
+	if err != nil {
+		return 0, err
+	}
+	return found.ID, nil
+}
+
+// addCampusFlag adds the --campus flag of the commands defaulting to the campus_id of the config file
+func addCampusFlag(cmd *cobra.Command, api *ftapi.APIInterface) {
+	cmd.Flags().String("campus", "", "Campus ID, name or slug, defaults to the campus_id of the config file")
+	_ = cmd.RegisterFlagCompletionFunc("campus", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeCampuses(*api), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+// checkCampusFlag returns an error when neither --campus nor the campus_id of the config file is set
+func checkCampusFlag(cmd *cobra.Command) error {
+	if campus, _ := cmd.Flags().GetString("campus"); campus == "" && viper.GetInt("campus_id") <= 0 {
+		return errors.New("--campus is required when campus_id is not set in the config file")
+	}
+	return nil
+}
+
+// campusFlagID returns the ID of the campus of the --campus flag, or the campus_id of the config file when it isn't set
+func campusFlagID(cmd *cobra.Command, api ftapi.APIInterface) (int, error) {
+	if campus, _ := cmd.Flags().GetString("campus"); campus != "" {
+		return resolveCampusID(cmd.Context(), api, campus)
+	}
+	return viper.GetInt("campus_id"), nil
+}
+
+// NewCampusCmd creates the campus cmd
+func NewCampusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "campus",
+		Short: "Look up the 42 campuses",
+		Long: `Look up the 42 campuses.
+
+A campus can be given by ID, name or slug, the name in lower case with dashes like sao-paulo.`,
+	}
+}
+
+var campusCmd = NewCampusCmd()
+
+func init() {
+	rootCmd.AddCommand(campusCmd)
+}
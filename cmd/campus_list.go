@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"goft/pkg/ftapi"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// campusesTable returns the table and csv view of campuses
+func campusesTable(campuses ...*ftapi.Campus) table {
+	t := table{Header: []string{"ID", "NAME", "SLUG", "CITY", "COUNTRY", "TIME ZONE", "USERS", "ACTIVE"}}
+	for _, campus := range campuses {
+		t.Rows = append(t.Rows, []string{
+			strconv.Itoa(campus.ID),
+			campus.Name,
+			campus.Slug(),
+			campus.City,
+			campus.Country,
+			campus.TimeZone,
+			strconv.Itoa(campus.UsersCount),
+			strconv.FormatBool(campus.Active),
+		})
+	}
+	return t
+}
+
+// NewCampusListCmd creates the campus list cmd
+func NewCampusListCmd(api *ftapi.APIInterface) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the campuses",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := url.Values{}
+			if search, _ := cmd.Flags().GetString("search"); search != "" {
+				params.Set("search[name]", search)
+			}
+			if country, _ := cmd.Flags().GetString("country"); country != "" {
+				params.Set("filter[country]", country)
+			}
+			if active, _ := cmd.Flags().GetBool("active"); active {
+				params.Set("filter[active]", "true")
+			}
+			var campuses []*ftapi.Campus
+			err := (*api).ListCampusesContext(cmd.Context(), params).PageSize(ftapi.MaxPageSize).All(&campuses)
+			if err != nil {
+				return err
+			}
+			return printOutput(cmd, output{
+				Data:  campuses,
+				Table: campusesTable(campuses...),
+				Text: func(w io.Writer) {
+					for _, campus := range campuses {
+						_, _ = fmt.Fprintf(w, "%d\t%s (%s, %s)\n", campus.ID, campus.Name, campus.City, campus.Country)
+					}
+				},
+			})
+		},
+	}
+	cmd.Flags().String("search", "", "Only list the campuses whose name contains this text")
+	cmd.Flags().String("country", "", "Only list the campuses of this country")
+	cmd.Flags().Bool("active", false, "Only list the active campuses")
+	return cmd
+}
+
+var campusListCmd = NewCampusListCmd(&API)
+
+func init() {
+	campusCmd.AddCommand(campusListCmd)
+}
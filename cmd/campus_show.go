@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"goft/pkg/ftapi"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// formatCampusText describes a campus for campus show
+func formatCampusText(campus *ftapi.Campus) string {
+	language := ""
+	if campus.Language != nil {
+		language = campus.Language.Name
+	}
+	return fmt.Sprintf(`Id: %d
+Name: %s
+Slug: %s
+Time zone: %s
+Language: %s
+Address: %s
+City: %s %s
+Country: %s
+Website: %s
+Users: %d
+Active: %t
+Public: %t
+`,
+		campus.ID,
+		campus.Name,
+		campus.Slug(),
+		campus.TimeZone,
+		language,
+		campus.Address,
+		campus.Zip,
+		campus.City,
+		campus.Country,
+		campus.Website,
+		campus.UsersCount,
+		campus.Active,
+		campus.Public,
+	)
+}
+
+// NewCampusShowCmd creates the campus show cmd
+func NewCampusShowCmd(api *ftapi.APIInterface) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show campus",
+		Short: "Show a campus given by ID, name or slug",
+		Annotations: map[string]string{
+			argsAnnotation: "campus",
+		},
+		ValidArgsFunction: completeArgs(api),
+		Args:              cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			campus, err := resolveCampus(cmd.Context(), *api, args[0])
+			if err != nil {
+				return err
+			}
+			return printOutput(cmd, output{
+				Data:  campus,
+				Table: campusesTable(campus),
+				Text: func(w io.Writer) {
+					_, _ = fmt.Fprint(w, formatCampusText(campus))
+				},
+			})
+		},
+	}
+}
+
+var campusShowCmd = NewCampusShowCmd(&API)
+
+func init() {
+	campusCmd.AddCommand(campusShowCmd)
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"goft/pkg/ftapi"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newCampusServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/campus":
+			if req.URL.Query().Get("search[name]") == "paris" {
+				_, _ = rw.Write([]byte(`[{"id":1,"name":"Paris","city":"Paris","country":"France"}]`))
+				return
+			}
+			_, _ = rw.Write([]byte(`[
+				{"id":1,"name":"Paris","city":"Paris","country":"France"},
+				{"id":20,"name":"São Paulo","city":"São Paulo","country":"Brazil"},
+				{"id":21,"name":"Benguerir","city":"Benguerir","country":"Morocco"},
+				{"id":55,"name":"Benguerir","city":"Benguerir","country":"Morocco"}
+			]`))
+		case "/campus/21":
+			_, _ = rw.Write([]byte(`{"id":21,"name":"Benguerir","time_zone":"Africa/Casablanca","city":"Benguerir","country":"Morocco","users_count":1337,"active":true}`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestResolveCampus(t *testing.T) {
+	server := newCampusServer(t)
+	var api ftapi.APIInterface = ftapi.New(server.URL, server.Client(), ftapi.WithRateLimiter(nil))
+
+	campus, err := resolveCampus(context.Background(), api, "sao-paulo")
+	assert.Nil(t, err)
+	assert.Equal(t, 20, campus.ID)
+	campus, err = resolveCampus(context.Background(), api, "PARIS")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, campus.ID)
+	campus, err = resolveCampus(context.Background(), api, "21")
+	assert.Nil(t, err)
+	assert.Equal(t, "Africa/Casablanca", campus.TimeZone)
+
+	_, err = resolveCampus(context.Background(), api, "benguerir")
+	assert.EqualError(t, err, "campus benguerir is ambiguous, use the ID of one of Benguerir (21), Benguerir (55)")
+	_, err = resolveCampus(context.Background(), api, "atlantis")
+	assert.True(t, errors.Is(err, ftapi.ErrNotFound))
+
+	id, err := resolveCampusID(context.Background(), &baseMockAPI{}, "42")
+	assert.Nil(t, err)
+	assert.Equal(t, 42, id)
+}
+
+func TestCampusList(t *testing.T) {
+	server := newCampusServer(t)
+	var api ftapi.APIInterface = ftapi.New(server.URL, server.Client(), ftapi.WithRateLimiter(nil))
+	stdout := bytes.NewBufferString("")
+	listCmd := NewCampusListCmd(&api)
+	listCmd.SetArgs([]string{"--search", "paris"})
+	listCmd.SetOut(stdout)
+	assert.Nil(t, listCmd.Execute())
+	assert.Equal(t, "1\tParis (Paris, France)\n", stdout.String())
+}
+
+func TestCampusShow(t *testing.T) {
+	server := newCampusServer(t)
+	var api ftapi.APIInterface = ftapi.New(server.URL, server.Client(), ftapi.WithRateLimiter(nil))
+	stdout := bytes.NewBufferString("")
+	showCmd := NewCampusShowCmd(&api)
+	showCmd.Flags().StringP("output", "o", "", outputFlagUsage)
+	showCmd.SetArgs([]string{"21", "-o", "csv"})
+	showCmd.SetOut(stdout)
+	assert.Nil(t, showCmd.Execute())
+	assert.Equal(t, "ID,NAME,SLUG,CITY,COUNTRY,TIME ZONE,USERS,ACTIVE\n21,Benguerir,benguerir,Benguerir,Morocco,Africa/Casablanca,1337,true\n", stdout.String())
+}
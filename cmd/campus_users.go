@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"goft/pkg/ftapi"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// campusUsersParams builds the filters of campus users from its flags
+func campusUsersParams(cmd *cobra.Command) url.Values {
+	params := url.Values{}
+	for flag, filter := range map[string]string{
+		"pool-month": "filter[pool_month]",
+		"pool-year":  "filter[pool_year]",
+		"kind":       "filter[kind]",
+	} {
+		if value, _ := cmd.Flags().GetString(flag); value != "" {
+			params.Set(filter, value)
+		}
+	}
+	if cmd.Flags().Changed("staff") {
+		staff, _ := cmd.Flags().GetBool("staff")
+		params.Set("filter[staff?]", strconv.FormatBool(staff))
+	}
+	return params
+}
+
+// NewCampusUsersCmd creates the campus users cmd
+func NewCampusUsersCmd(api *ftapi.APIInterface) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "users [campus]",
+		Short: "List the users of a campus",
+		Long: `List the users of a campus given by ID, name or slug, it defaults to the campus_id of the config file.
+
+The users are printed as they are fetched with the default output, csv and jsonl,
+which suit big cohorts, the other formats wait for the whole list:
+  goft campus users benguerir --pool-year 2021 --pool-month july -o csv > piscine.csv`,
+		Annotations: map[string]string{
+			argsAnnotation: "campus",
+		},
+		ValidArgsFunction: completeArgs(api),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
+				return err
+			}
+			if len(args) == 0 && viper.GetInt("campus_id") <= 0 {
+				return errors.New("campus is required when campus_id is not set in the config file")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			campusID := viper.GetInt("campus_id")
+			if len(args) == 1 {
+				var err error
+				campusID, err = resolveCampusID(cmd.Context(), *api, args[0])
+				if err != nil {
+					return err
+				}
+			}
+			params := campusUsersParams(cmd)
+			var pager *ftapi.Pager
+			// Campus users can't be filtered by cursus, the cursus users can be filtered by campus
+			if cursusID, _ := cmd.Flags().GetInt("cursus"); cursusID > 0 {
+				params.Set("filter[primary_campus_id]", strconv.Itoa(campusID))
+				pager = (*api).ListCursusUsersContext(cmd.Context(), cursusID, params)
+			} else {
+				pager = (*api).ListCampusUsersContext(cmd.Context(), campusID, params)
+			}
+			pager.PageSize(ftapi.MaxPageSize)
+
+			if !isStreamedFormat(outputFormat(cmd)) {
+				var users []*ftapi.User
+				if err := pager.All(&users); err != nil {
+					return err
+				}
+				return printOutput(cmd, output{Data: users, Table: usersTable(users...)})
+			}
+			rows := newRowWriter(cmd, usersTable().Header)
+			for pager.Next() {
+				var user ftapi.User
+				if err := pager.Decode(&user); err != nil {
+					return err
+				}
+				text := fmt.Sprintf("%s\t%s %s\t%s", user.Login, user.FirstName, user.LastName, user.Email)
+				if err := rows.Write(user, usersTable(&user).Rows[0], text); err != nil {
+					return err
+				}
+			}
+			return pager.Err()
+		},
+	}
+	cmd.Flags().String("pool-month", "", "Only list the users of this piscine month, like july")
+	cmd.Flags().String("pool-year", "", "Only list the users of this piscine year, like 2021")
+	cmd.Flags().String("kind", "", "Only list the users of this kind: admin, student or external")
+	cmd.Flags().Bool("staff", false, "Only list the staff members, --staff=false only lists the others")
+	cmd.Flags().Int("cursus", 0, "Only list the users of this cursus ID, like 21 for 42cursus")
+	return cmd
+}
+
+var campusUsersCmd = NewCampusUsersCmd(&API)
+
+func init() {
+	campusCmd.AddCommand(campusUsersCmd)
+}
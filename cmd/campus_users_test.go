@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"goft/pkg/ftapi"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func executeCampusUsers(t *testing.T, handler http.HandlerFunc, args ...string) string {
+	server := httptest.NewServer(handler)
+	defer server.Close()
+	var api ftapi.APIInterface = ftapi.New(server.URL, server.Client(), ftapi.WithRateLimiter(nil))
+	stdout := bytes.NewBufferString("")
+	usersCmd := NewCampusUsersCmd(&api)
+	usersCmd.Flags().StringP("output", "o", "", outputFlagUsage)
+	usersCmd.SetArgs(args)
+	usersCmd.SetOut(stdout)
+	assert.Nil(t, usersCmd.Execute())
+	return stdout.String()
+}
+
+func TestCampusUsers(t *testing.T) {
+	stdout := executeCampusUsers(t, func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/campus/21/users", req.URL.Path)
+		query := req.URL.Query()
+		assert.Equal(t, "july", query.Get("filter[pool_month]"))
+		assert.Equal(t, "2021", query.Get("filter[pool_year]"))
+		assert.Equal(t, "false", query.Get("filter[staff?]"))
+		assert.Equal(t, "", query.Get("filter[kind]"))
+		rw.Header().Set("X-Total", "3")
+		rw.Header().Set("X-Per-Page", "2")
+		if query.Get("page[number]") == "1" {
+			_, _ = rw.Write([]byte(`[{"id":1,"login":"spoody","email":"spoody@1337.ma"},{"id":2,"login":"jdoe"}]`))
+			return
+		}
+		_, _ = rw.Write([]byte(`[{"id":3,"login":"asmith"}]`))
+	}, "21", "--pool-month", "july", "--pool-year", "2021", "--staff=false", "-o", "jsonl")
+	assert.Equal(t, `{"id":1,"login":"spoody","email":"spoody@1337.ma"}
+{"id":2,"login":"jdoe"}
+{"id":3,"login":"asmith"}
+`, stdout)
+}
+
+func TestCampusUsersOfCursus(t *testing.T) {
+	stdout := executeCampusUsers(t, func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/cursus/9/users", req.URL.Path)
+		assert.Equal(t, "21", req.URL.Query().Get("filter[primary_campus_id]"))
+		_, _ = rw.Write([]byte(`[{"id":1,"login":"spoody","first_name":"Mehdi","last_name":"Bounya","pool_month":"july","pool_year":"2021"}]`))
+	}, "21", "--cursus", "9", "-o", "csv")
+	assert.Equal(t, "ID,LOGIN,EMAIL,FIRST NAME,LAST NAME,STAFF,CORRECTION POINTS,WALLET,POOL,CAMPUS\n1,spoody,,Mehdi,Bounya,false,0,0,july/2021,\n", stdout)
+}
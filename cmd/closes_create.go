@@ -7,16 +7,22 @@ import (
 	"goft/pkg/ftapi"
 )
 
+// closeKinds are the kinds a close can have
+var closeKinds = []string{
+	"agu",
+	"black_hole",
+	"deserter",
+	"non_admitted",
+	"serious_misconduct",
+	"social_security",
+	"other",
+}
+
 func isValidKind(kind string) bool {
-	switch kind {
-	case "agu",
-		"black_hole",
-		"deserter",
-		"non_admitted",
-		"serious_misconduct",
-		"social_security",
-		"other":
-		return true
+	for _, closeKind := range closeKinds {
+		if kind == closeKind {
+			return true
+		}
 	}
 	return false
 }
@@ -25,13 +31,18 @@ func isValidKind(kind string) bool {
 func NewCloseCreateCmd(api *ftapi.APIInterface) *cobra.Command {
 	// login - kind - reason -
 	return &cobra.Command{
-		Use:   "create login kind reason closer_login",
+		Use:   "create login kind reason [closer_login]",
 		Short: "Create a new close for a user",
 		Long: `This command requires the Basic staff role
 
-kind must be one of the following options: agu, black_hole, deserter, non_admitted, serious_misconduct, social_security or other`,
+kind must be one of the following options: agu, black_hole, deserter, non_admitted, serious_misconduct, social_security or other
+closer_login defaults to me, the user logged in with goft auth login`,
+		Annotations: map[string]string{
+			argsAnnotation: "login,kind,,login",
+		},
+		ValidArgsFunction: completeArgs(api),
 		Args: func(cmd *cobra.Command, args []string) error {
-			if err := cobra.ExactArgs(4)(cmd, args); err != nil {
+			if err := cobra.RangeArgs(3, 4)(cmd, args); err != nil {
 				return err
 			}
 			if !isValidKind(args[1]) {
@@ -40,19 +51,32 @@ kind must be one of the following options: agu, black_hole, deserter, non_admitt
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			closer, err := (*api).GetUserByLogin(args[3])
+			login, err := resolveLogin(cmd.Context(), *api, args[0])
+			if err != nil {
+				return err
+			}
+			closerLogin := "me"
+			if len(args) == 4 {
+				closerLogin = args[3]
+			}
+			var closer *ftapi.User
+			if closerLogin == "me" {
+				closer, err = (*api).GetMeContext(cmd.Context())
+			} else {
+				closer, err = (*api).GetUserByLoginContext(cmd.Context(), closerLogin)
+			}
 			if err != nil {
 				return err
 			}
 			if closer == nil || closer.ID == 0 {
 				return errors.New("couldn't get closer")
 			}
-			err = (*api).CreateClose(&ftapi.Close{
+			err = (*api).CreateCloseContext(cmd.Context(), &ftapi.Close{
 				Kind: args[1],
 				Reason: args[2],
 				CommunityServices: nil,
 				User: &ftapi.User{
-					Login: args[0],
+					Login: login,
 				},
 				Closer: &ftapi.User{
 					ID: closer.ID,
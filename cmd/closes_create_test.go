@@ -2,70 +2,36 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"goft/pkg/ftapi"
-	"io"
 	"io/ioutil"
-	"net/http"
-	"os"
 	"testing"
 )
 
 type createCloseMockAPI struct {
+	baseMockAPI
 	t *testing.T
 }
-func (m *createCloseMockAPI) Get(url string) (*http.Response, error) {
-	return nil, nil
-}
-func (m *createCloseMockAPI) Delete(url string, contentType string, body io.Reader) (*http.Response, error) {
-	return nil, nil
-}
-func (m *createCloseMockAPI) Post(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *createCloseMockAPI) PostJSON(url string, data interface{}) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *createCloseMockAPI) Patch(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *createCloseMockAPI) PatchJSON(url string, data interface{}) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *createCloseMockAPI) SetUserImage(login string, img *os.File) error {
-	return nil
-}
-func (m *createCloseMockAPI) CreateUser(user *ftapi.User, campusID int) error {
-	return nil
-}
-func (m *createCloseMockAPI) CreateClose(close *ftapi.Close) error {
+func (m *createCloseMockAPI) CreateCloseContext(ctx context.Context, close *ftapi.Close) error {
 	assert.Equal(m.t, "other", close.Kind)
 	assert.Equal(m.t, "Testing purposes", close.Reason)
 	assert.Equal(m.t, "spoody", close.User.Login)
 	assert.Equal(m.t, 42, close.Closer.ID)
 	return nil
 }
-func (m *createCloseMockAPI) GetUserByLogin(login string) (*ftapi.User, error) {
+func (m *createCloseMockAPI) GetUserByLoginContext(ctx context.Context, login string) (*ftapi.User, error) {
 	assert.Equal(m.t, "spoody", login)
 	return &ftapi.User{
 		ID: 42,
 	}, nil
 }
-func (m *createCloseMockAPI) UpdateUser(login string, data *ftapi.User) error  {
-	return nil
-}
-func (m *createCloseMockAPI) AddCorrectionPoints(login string, points uint, reason string) error{
-	return nil
-}
-func (m *createCloseMockAPI) RemoveCorrectionPoints(login string, points uint, reason string) error{
-	return nil
-}
-func (m *createCloseMockAPI) GetUserAgus(login string) ([]ftapi.Agu, error) {
-	return nil, nil
-}
-func (m *createCloseMockAPI) CreateFreePastAgu(login string, duration int, reason string) error {
-	return nil
+func (m *createCloseMockAPI) GetMeContext(ctx context.Context) (*ftapi.User, error) {
+	return &ftapi.User{
+		ID: 42,
+		Login: "spoody",
+	}, nil
 }
 
 func TestNewCloseCreateCmd(t *testing.T) {
@@ -101,6 +67,15 @@ func TestCreateClose(t *testing.T) {
 	assert.Equal(t, "", string(out))
 }
 
+func TestCreateCloseByMe(t *testing.T) {
+	var api ftapi.APIInterface = &createCloseMockAPI{t: t}
+	testCmd := NewCloseCreateCmd(&api)
+	testCmd.SetArgs([]string{"me", "other", "Testing purposes"})
+	testCmd.SetOut(bytes.NewBufferString(""))
+	err := testCmd.Execute()
+	assert.Nil(t, err)
+}
+
 func TestCreateCloseInvalid(t *testing.T) {
 	var api ftapi.APIInterface = &createCloseMockAPI{t: t}
 	stdout := bytes.NewBufferString("")
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"goft/pkg/ftapi"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// argsAnnotation describes the positional arguments of a command, for completion and the recent values cache.
+// It is a comma separated list with one kind per argument: login, campus_id, campus, kind, project, profile or empty.
+// A campus_id is only an ID while a campus can also be a name or slug
+const argsAnnotation = "goft/args"
+
+// completionTimeout bounds the API requests made while completing so tab never hangs on the network
+const completionTimeout = 2 * time.Second
+
+// maxRecentValues is the number of logins and campus IDs remembered for completion
+const maxRecentValues = 50
+
+// recentValues are the logins and campus IDs recently used as arguments, most recent first
+type recentValues struct {
+	Logins    []string `json:"logins,omitempty"`
+	CampusIDs []string `json:"campus_ids,omitempty"`
+}
+
+// recentValuesPath returns the file caching the recent values of the profile in use
+func recentValuesPath() string {
+	return profileDir() + "/recent.json"
+}
+
+// readRecentValues reads the recent values cache at path, it is empty when the file can't be read
+func readRecentValues(path string) recentValues {
+	var recent recentValues
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return recent
+	}
+	_ = json.Unmarshal(data, &recent)
+	return recent
+}
+
+// writeRecentValues writes the recent values cache at path
+func writeRecentValues(path string, recent recentValues) error {
+	data, err := json.Marshal(recent)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// pushRecent moves value to the front of values, keeping at most maxRecentValues
+func pushRecent(values []string, value string) []string {
+	result := []string{value}
+	for _, v := range values {
+		if v != value && len(result) < maxRecentValues {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// argKind returns the kind of the positional argument at index in the argsAnnotation of cmd
+func argKind(cmd *cobra.Command, index int) string {
+	kinds := strings.Split(cmd.Annotations[argsAnnotation], ",")
+	if index >= len(kinds) {
+		return ""
+	}
+	return kinds[index]
+}
+
+// rememberArgs adds the logins and campus IDs used by cmd to the recent values cache at path
+func rememberArgs(cmd *cobra.Command, args []string, path string) error {
+	recent := readRecentValues(path)
+	changed := false
+	for i, arg := range args {
+		switch argKind(cmd, i) {
+		case "login":
+			if arg != "me" {
+				recent.Logins = pushRecent(recent.Logins, arg)
+				changed = true
+			}
+		case "campus_id", "campus":
+			recent.CampusIDs = pushRecent(recent.CampusIDs, arg)
+			changed = true
+		}
+	}
+	if flag := cmd.Flag("user"); flag != nil && flag.Changed && flag.Value.String() != "me" {
+		recent.Logins = pushRecent(recent.Logins, flag.Value.String())
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return writeRecentValues(path, recent)
+}
+
+// completeArgs returns the completion function of a command described with argsAnnotation
+func completeArgs(api *ftapi.APIInterface) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		switch argKind(cmd, len(args)) {
+		case "login":
+			return completeLogins(cmd, args, toComplete)
+		case "campus_id":
+			recent := readRecentValues(recentValuesPath())
+			campusIDs := recent.CampusIDs
+			if campusID := viper.GetInt("campus_id"); campusID > 0 {
+				campusIDs = pushRecent(campusIDs, strconv.Itoa(campusID))
+			}
+			return campusIDs, cobra.ShellCompDirectiveNoFileComp
+		case "campus":
+			return completeCampuses(*api), cobra.ShellCompDirectiveNoFileComp
+		case "kind":
+			return closeKinds, cobra.ShellCompDirectiveNoFileComp
+		case "project":
+			return completeProjects(cmd, *api), cobra.ShellCompDirectiveNoFileComp
+		case "profile":
+			names, _ := readProfileNames(cfgFile)
+			return names, cobra.ShellCompDirectiveNoFileComp
+		}
+		return nil, cobra.ShellCompDirectiveDefault
+	}
+}
+
+// completeLogins suggests the recently used logins, and me when logged in
+func completeLogins(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	logins := readRecentValues(recentValuesPath()).Logins
+	if userTokens != nil && userTokens.Cached() {
+		logins = append([]string{"me"}, logins...)
+	}
+	return logins, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProjects suggests the slugs of the first page of projects of the --user flag or the default login
+func completeProjects(cmd *cobra.Command, api ftapi.APIInterface) []string {
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+	login := defaultLogin()
+	if flag := cmd.Flag("user"); flag != nil && flag.Value.String() != "" {
+		login = flag.Value.String()
+	}
+	login, err := resolveLogin(ctx, api, login)
+	if err != nil {
+		return nil
+	}
+	projects, err := api.GetUserProjectsContext(ctx, login, nil, nil, 1)
+	if err != nil {
+		return nil
+	}
+	slugs := make([]string, 0, len(projects))
+	for _, project := range projects {
+		slugs = append(slugs, project.Project.Slug)
+	}
+	return slugs
+}
+
+// completeCampuses suggests the recently used campuses, the one of the config file and the slugs of all the campuses
+func completeCampuses(api ftapi.APIInterface) []string {
+	campuses := readRecentValues(recentValuesPath()).CampusIDs
+	if campusID := viper.GetInt("campus_id"); campusID > 0 {
+		campuses = pushRecent(campuses, strconv.Itoa(campusID))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), completionTimeout)
+	defer cancel()
+	var all []*ftapi.Campus
+	if err := api.ListCampusesContext(ctx, nil).PageSize(ftapi.MaxPageSize).All(&all); err != nil {
+		return campuses
+	}
+	for _, campus := range all {
+		campuses = append(campuses, campus.Slug())
+	}
+	return campuses
+}
+
+// NewCompletionCmd creates the completion cmd
+func NewCompletionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "completion bash|zsh|fish|powershell",
+		Short: "Generate the shell completion script",
+		Annotations: map[string]string{
+			credentialsAnnotation: "none",
+		},
+		ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+		Args:      cobra.ExactValidArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			switch args[0] {
+			case "bash":
+				return cmd.Root().GenBashCompletion(out)
+			case "zsh":
+				return cmd.Root().GenZshCompletion(out)
+			case "fish":
+				return cmd.Root().GenFishCompletion(out, true)
+			case "powershell":
+				return cmd.Root().GenPowerShellCompletion(out)
+			}
+			return fmt.Errorf("unsupported shell '%s'", args[0])
+		},
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(NewCompletionCmd())
+}
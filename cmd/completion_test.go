@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"goft/pkg/ftapi"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+type completionMockAPI struct {
+	baseMockAPI
+	login string
+}
+
+func (m *completionMockAPI) GetUserProjectsContext(ctx context.Context, login string, filter_param map[string]string, range_param map[string]string, page_number int) ([]*ftapi.ProjectUser, error) {
+	m.login = login
+	return []*ftapi.ProjectUser{
+		{Project: ftapi.Project{Slug: "libft"}},
+		{Project: ftapi.Project{Slug: "ft_printf"}},
+	}, nil
+}
+
+func TestPushRecent(t *testing.T) {
+	values := pushRecent(nil, "spoody")
+	values = pushRecent(values, "mbouzaie")
+	values = pushRecent(values, "spoody")
+	assert.Equal(t, []string{"spoody", "mbouzaie"}, values)
+
+	for i := 0; i < maxRecentValues+10; i++ {
+		values = pushRecent(values, string(rune('a'+i)))
+	}
+	assert.Len(t, values, maxRecentValues)
+}
+
+func TestRememberArgs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "recent.json")
+	cmd := &cobra.Command{Annotations: map[string]string{argsAnnotation: "login,kind,,login"}}
+
+	assert.Nil(t, rememberArgs(cmd, []string{"spoody", "agu", "reason", "me"}, path))
+	assert.Nil(t, rememberArgs(cmd, []string{"mbouzaie", "agu", "reason"}, path))
+	assert.Equal(t, recentValues{Logins: []string{"mbouzaie", "spoody"}}, readRecentValues(path))
+
+	cmd = &cobra.Command{Annotations: map[string]string{argsAnnotation: ",,,,campus_id"}}
+	assert.Nil(t, rememberArgs(cmd, []string{"a@b.c", "first", "last", "external", "21"}, path))
+	assert.Equal(t, []string{"21"}, readRecentValues(path).CampusIDs)
+}
+
+func TestCompleteArgs(t *testing.T) {
+	api := ftapi.APIInterface(&completionMockAPI{})
+	closeCmd := NewCloseCreateCmd(&api)
+	kinds, directive := closeCmd.ValidArgsFunction(closeCmd, []string{"spoody"}, "")
+	assert.Equal(t, closeKinds, kinds)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+
+	_, directive = closeCmd.ValidArgsFunction(closeCmd, []string{"spoody", "agu"}, "")
+	assert.Equal(t, cobra.ShellCompDirectiveDefault, directive)
+
+	cloneCmd := NewCloneProjectCmd(&api)
+	cloneCmd.PersistentFlags().StringP("user", "u", "", "")
+	assert.Nil(t, cloneCmd.PersistentFlags().Set("user", "spoody"))
+	slugs, directive := cloneCmd.ValidArgsFunction(cloneCmd, nil, "")
+	assert.Equal(t, []string{"libft", "ft_printf"}, slugs)
+	assert.Equal(t, cobra.ShellCompDirectiveNoFileComp, directive)
+	assert.Equal(t, "spoody", api.(*completionMockAPI).login)
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewConfigCmd creates the config cmd
+func NewConfigCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "config",
+		Short: "Manage goft's config file",
+	}
+}
+
+var configCmd = NewConfigCmd()
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+}
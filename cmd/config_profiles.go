@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+)
+
+// NewConfigProfilesCmd creates the config profiles cmd
+func NewConfigProfilesCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "profiles",
+		Short: "Manage the profiles of the config file",
+		Long: `Profiles are set under the profiles key of the config file, each one can override
+any top level setting like client_id, client_secret, scopes, api_endpoint, campus_id or smtp.
+
+The profile is selected with --profile, $GOFT_PROFILE or goft config profiles use, in that order.`,
+	}
+}
+
+// readProfileNames returns the sorted names of the profiles defined in the config file at path
+func readProfileNames(path string) ([]string, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	err := v.ReadInConfig()
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for name := range v.GetStringMap("profiles") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// setConfigValue sets a top level key of the yaml config file at path, keeping its comments
+func setConfigValue(path string, key string, value string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var document yaml.Node
+	err = yaml.Unmarshal(data, &document)
+	if err != nil {
+		return err
+	}
+	if len(document.Content) == 0 {
+		document.Kind = yaml.DocumentNode
+		document.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	mapping := document.Content[0]
+	valueNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	found := false
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content[i+1] = valueNode
+			found = true
+		}
+	}
+	if !found {
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, valueNode)
+	}
+	data, err = yaml.Marshal(&document)
+	if err != nil {
+		return err
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, stat.Mode().Perm())
+}
+
+var configProfilesCmd = NewConfigProfilesCmd()
+
+func init() {
+	configCmd.AddCommand(configProfilesCmd)
+}
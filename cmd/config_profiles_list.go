@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// NewConfigProfilesListCmd create the config profiles list cmd
+func NewConfigProfilesListCmd(configFile *string, current *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the profiles of the config file, the one in use is marked with *",
+		Annotations: map[string]string{
+			credentialsAnnotation: "none",
+		},
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names, err := readProfileNames(*configFile)
+			if err != nil {
+				return err
+			}
+			for _, name := range names {
+				marker := " "
+				if name == *current {
+					marker = "*"
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n", marker, name)
+			}
+			return nil
+		},
+	}
+}
+
+var configProfilesListCmd = NewConfigProfilesListCmd(&cfgFile, &profile)
+
+func init() {
+	configProfilesCmd.AddCommand(configProfilesListCmd)
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const profilesConfig = `# Shared by all profiles
+client_id: "id"
+client_secret: "secret"
+profiles:
+  benguerir:
+    campus_id: 21
+  staging:
+    api_endpoint: "https://staging.intra.42.fr/v2"
+`
+
+func tempConfig(t *testing.T, content string) string {
+	dir, err := ioutil.TempDir("", "goft")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+	path := filepath.Join(dir, "config.yml")
+	err = ioutil.WriteFile(path, []byte(content), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigProfilesList(t *testing.T) {
+	path := tempConfig(t, profilesConfig)
+	current := "staging"
+	stdout := bytes.NewBufferString("")
+	testCmd := NewConfigProfilesListCmd(&path, &current)
+	testCmd.SetArgs([]string{})
+	testCmd.SetOut(stdout)
+	assert.Nil(t, testCmd.Execute())
+	assert.Equal(t, "  benguerir\n* staging\n", stdout.String())
+}
+
+func TestConfigProfilesUse(t *testing.T) {
+	path := tempConfig(t, profilesConfig)
+	stdout := bytes.NewBufferString("")
+	testCmd := NewConfigProfilesUseCmd(&path)
+	testCmd.SetArgs([]string{"benguerir"})
+	testCmd.SetOut(stdout)
+	assert.Nil(t, testCmd.Execute())
+	assert.Equal(t, "Now using profile benguerir\n", stdout.String())
+
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.Contains(t, string(data), "# Shared by all profiles")
+	assert.Contains(t, string(data), "profile: benguerir")
+
+	testCmd = NewConfigProfilesUseCmd(&path)
+	testCmd.SetArgs([]string{"staging"})
+	testCmd.SetOut(stdout)
+	assert.Nil(t, testCmd.Execute())
+	data, _ = ioutil.ReadFile(path)
+	assert.NotContains(t, string(data), "profile: benguerir")
+	assert.Contains(t, string(data), "profile: staging")
+}
+
+func TestConfigProfilesUseUnknown(t *testing.T) {
+	path := tempConfig(t, profilesConfig)
+	testCmd := NewConfigProfilesUseCmd(&path)
+	testCmd.SetArgs([]string{"paris"})
+	testCmd.SetOut(bytes.NewBufferString(""))
+	testCmd.SetErr(bytes.NewBufferString(""))
+	assert.NotNil(t, testCmd.Execute())
+}
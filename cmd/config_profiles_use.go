@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// NewConfigProfilesUseCmd create the config profiles use cmd
+func NewConfigProfilesUseCmd(configFile *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "use profile",
+		Short: "Set the profile used by default",
+		Long: `Set the profile used when neither --profile nor $GOFT_PROFILE are set,
+it is saved as the profile key of the config file.`,
+		Annotations: map[string]string{
+			argsAnnotation:        "profile",
+			credentialsAnnotation: "none",
+		},
+		ValidArgsFunction: completeArgs(nil),
+		Args:              cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			names, err := readProfileNames(*configFile)
+			if err != nil {
+				return err
+			}
+			found := false
+			for _, name := range names {
+				found = found || name == args[0]
+			}
+			if !found {
+				return fmt.Errorf("profile %s not found in %s", args[0], *configFile)
+			}
+			err = setConfigValue(*configFile, "profile", args[0])
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Now using profile %s\n", args[0])
+			return nil
+		},
+	}
+}
+
+var configProfilesUseCmd = NewConfigProfilesUseCmd(&cfgFile)
+
+func init() {
+	configProfilesCmd.AddCommand(configProfilesUseCmd)
+}
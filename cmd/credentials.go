@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// runCredentialCommand runs command with the shell, like git's credential helpers,
+// and returns the first line it prints as the client secret.
+// The command inherits stdin and stderr so it can prompt for a passphrase.
+func runCredentialCommand(command string, stdin io.Reader, stderr io.Writer) (string, error) {
+	stdout := &bytes.Buffer{}
+	c := exec.Command("sh", "-c", command)
+	c.Stdin = stdin
+	c.Stdout = stdout
+	c.Stderr = stderr
+	if err := c.Run(); err != nil {
+		return "", fmt.Errorf("credential_command failed: %w", err)
+	}
+	secret := strings.TrimSpace(strings.SplitN(stdout.String(), "\n", 2)[0])
+	if secret == "" {
+		return "", errors.New("credential_command didn't print a client secret")
+	}
+	return secret, nil
+}
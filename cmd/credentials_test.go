@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunCredentialCommand(t *testing.T) {
+	secret, err := runCredentialCommand("printf 'thesecret\nignored\n'", bytes.NewBufferString(""), bytes.NewBufferString(""))
+	assert.Nil(t, err)
+	assert.Equal(t, "thesecret", secret)
+
+	secret, err = runCredentialCommand("read line && echo \"$line\"", bytes.NewBufferString("fromstdin\n"), bytes.NewBufferString(""))
+	assert.Nil(t, err)
+	assert.Equal(t, "fromstdin", secret)
+}
+
+func TestRunCredentialCommandFails(t *testing.T) {
+	stderr := bytes.NewBufferString("")
+	_, err := runCredentialCommand("echo oops >&2; exit 3", bytes.NewBufferString(""), stderr)
+	assert.NotNil(t, err)
+	assert.Equal(t, "oops\n", stderr.String())
+
+	_, err = runCredentialCommand("true", bytes.NewBufferString(""), stderr)
+	assert.EqualError(t, err, "credential_command didn't print a client secret")
+}
+
+func TestClientSecret(t *testing.T) {
+	defer func(secret string, command string) {
+		viper.Set("client_secret", secret)
+		viper.Set("credential_command", command)
+	}(viper.GetString("client_secret"), viper.GetString("credential_command"))
+
+	viper.Set("client_secret", "")
+	viper.Set("credential_command", "")
+	_, err := clientSecret()
+	assert.EqualError(t, err, "client_secret is required but not set in the config file nor with $GOFT_CLIENT_SECRET")
+
+	viper.Set("credential_command", "echo thesecret")
+	secret, err := clientSecret()
+	assert.Nil(t, err)
+	assert.Equal(t, "thesecret", secret)
+
+	// The command only runs once, the secret is kept for the next tokens
+	viper.Set("credential_command", "exit 1")
+	secret, err = clientSecret()
+	assert.Nil(t, err)
+	assert.Equal(t, "thesecret", secret)
+}
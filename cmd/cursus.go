@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"goft/pkg/ftapi"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// resolveCursusID returns the ID of the cursus given by ID or slug, like 21 or 42cursus
+func resolveCursusID(ctx context.Context, api ftapi.APIInterface, cursus string) (int, error) {
+	if id, err := strconv.Atoi(cursus); err == nil {
+		return id, nil
+	}
+	var found []*ftapi.Cursus
+	err := api.ListCursusContext(ctx, url.Values{"filter[slug]": {cursus}}).All(&found)
+	if err != nil {
+		return 0, err
+	}
+	if len(found) == 0 {
+		return 0, fmt.Errorf("cursus %s: %w", cursus, ftapi.ErrNotFound)
+	}
+	return found[0].ID, nil
+}
+
+// findCursusUser returns the enrollment of login in the cursus cursusID
+func findCursusUser(ctx context.Context, api ftapi.APIInterface, login string, cursusID int) (*ftapi.CursusUser, error) {
+	cursusUsers, err := api.GetUserCursusContext(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+	for _, cursusUser := range cursusUsers {
+		if cursusUser.CursusID == cursusID || (cursusUser.Cursus != nil && cursusUser.Cursus.ID == cursusID) {
+			return cursusUser, nil
+		}
+	}
+	return nil, fmt.Errorf("%s is not enrolled in the cursus %d: %w", login, cursusID, ftapi.ErrNotFound)
+}
+
+// parseDate parses the dates given to the cursus commands, like 2006-01-02 or 2006-01-02T15:04:05Z07:00
+func parseDate(value string) (time.Time, error) {
+	return parseDateIn(value, time.Local)
+}
+
+// parseDateIn is the same as parseDate but the dates without time zone are in loc
+func parseDateIn(value string, loc *time.Location) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02"} {
+		if date, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date '%s', must be like 2006-01-02 or 2006-01-02T15:04:05Z07:00", value)
+}
+
+// NewCursusCmd creates the cursus cmd
+func NewCursusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "cursus",
+		Short: "List the cursus and manage the enrollments of users",
+		Long: `List the cursus and manage the enrollments of users.
+
+A cursus can be given by ID or slug, like 21 or 42cursus.
+Dates are like 2006-01-02, in the local time zone, or 2006-01-02T15:04:05Z07:00.`,
+	}
+}
+
+var cursusCmd = NewCursusCmd()
+
+func init() {
+	rootCmd.AddCommand(cursusCmd)
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"goft/pkg/ftapi"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCursusAddCmd creates the cursus add cmd
+func NewCursusAddCmd(api *ftapi.APIInterface) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add login cursus",
+		Short: "Enroll a user in a cursus",
+		Long: `Enroll a user in a cursus given by ID or slug, like 21 or 42cursus.
+
+The enrollment begins now unless --begin-at is set:
+  goft cursus add norminet 42cursus --begin-at 2021-10-04`,
+		Annotations: map[string]string{
+			argsAnnotation: "login",
+		},
+		ValidArgsFunction: completeArgs(api),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+				return err
+			}
+			for _, flag := range []string{"begin-at", "end-at"} {
+				if value, _ := cmd.Flags().GetString(flag); value != "" {
+					if _, err := parseDate(value); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			login, err := resolveLogin(cmd.Context(), *api, args[0])
+			if err != nil {
+				return err
+			}
+			cursusID, err := resolveCursusID(cmd.Context(), *api, args[1])
+			if err != nil {
+				return err
+			}
+			user, err := (*api).GetUserByLoginContext(cmd.Context(), login)
+			if err != nil {
+				return err
+			}
+			beginAt := time.Now()
+			if value, _ := cmd.Flags().GetString("begin-at"); value != "" {
+				beginAt, _ = parseDate(value)
+			}
+			var endAt *time.Time
+			if value, _ := cmd.Flags().GetString("end-at"); value != "" {
+				date, _ := parseDate(value)
+				endAt = &date
+				if !endAt.After(beginAt) {
+					return errors.New("--end-at must be after the beginning of the enrollment")
+				}
+			}
+			cursusUser, err := (*api).AddUserToCursusContext(cmd.Context(), user.ID, cursusID, beginAt, endAt)
+			if err != nil {
+				return err
+			}
+			if isDryRun(cmd) {
+				return nil
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s enrolled in the cursus %s from %s, cursus_user %d\n", login, args[1], beginAt.Format("2006-01-02 15:04"), cursusUser.ID)
+			return nil
+		},
+	}
+	cmd.Flags().String("begin-at", "", "Beginning of the enrollment, defaults to now")
+	cmd.Flags().String("end-at", "", "End of the enrollment, none by default")
+	return cmd
+}
+
+var cursusAddCmd = NewCursusAddCmd(&API)
+
+func init() {
+	cursusCmd.AddCommand(cursusAddCmd)
+}
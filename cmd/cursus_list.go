@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"goft/pkg/ftapi"
+	"io"
+	"net/url"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCursusListCmd creates the cursus list cmd
+func NewCursusListCmd(api *ftapi.APIInterface) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the cursus",
+		Args:  cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params := url.Values{}
+			if search, _ := cmd.Flags().GetString("search"); search != "" {
+				params.Set("search[name]", search)
+			}
+			var cursus []*ftapi.Cursus
+			err := (*api).ListCursusContext(cmd.Context(), params).PageSize(ftapi.MaxPageSize).All(&cursus)
+			if err != nil {
+				return err
+			}
+			t := table{Header: []string{"ID", "NAME", "SLUG", "KIND"}}
+			for _, c := range cursus {
+				t.Rows = append(t.Rows, []string{strconv.Itoa(c.ID), c.Name, c.Slug, c.Kind})
+			}
+			return printOutput(cmd, output{
+				Data:  cursus,
+				Table: t,
+				Text: func(w io.Writer) {
+					for _, c := range cursus {
+						_, _ = fmt.Fprintf(w, "%d\t%s (%s)\n", c.ID, c.Name, c.Slug)
+					}
+				},
+			})
+		},
+	}
+	cmd.Flags().String("search", "", "Only list the cursus whose name contains this text")
+	return cmd
+}
+
+var cursusListCmd = NewCursusListCmd(&API)
+
+func init() {
+	cursusCmd.AddCommand(cursusListCmd)
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"goft/pkg/ftapi"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newCursusServer serves the enrollment of spoody in 42cursus and records the bodies of the write requests
+func newCursusServer(t *testing.T, bodies *[]string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			body, _ := ioutil.ReadAll(req.Body)
+			*bodies = append(*bodies, req.Method+" "+req.URL.Path+" "+string(body))
+		}
+		switch {
+		case req.URL.Path == "/cursus" && req.URL.Query().Get("filter[slug]") == "42cursus":
+			_, _ = rw.Write([]byte(`[{"id":21,"name":"42cursus","slug":"42cursus"}]`))
+		case req.URL.Path == "/cursus":
+			_, _ = rw.Write([]byte(`[]`))
+		case req.URL.Path == "/users/spoody":
+			_, _ = rw.Write([]byte(`{"id":37,"login":"spoody"}`))
+		case req.URL.Path == "/users/spoody/cursus_users":
+			_, _ = rw.Write([]byte(`[{"id":3,"cursus_id":9,"cursus":{"id":9,"slug":"c-piscine"}},{"id":7,"cursus_id":21,"grade":"Learner","cursus":{"id":21,"slug":"42cursus"}}]`))
+		case req.URL.Path == "/cursus_users" && req.Method == http.MethodPost:
+			rw.WriteHeader(http.StatusCreated)
+			_, _ = rw.Write([]byte(`{"id":8,"cursus_id":21}`))
+		case req.URL.Path == "/cursus_users/7" && req.Method == http.MethodGet:
+			_, _ = rw.Write([]byte(`{"id":7,"grade":"Learner","end_at":"2022-06-30T22:00:00Z","user":{"login":"spoody"},"cursus":{"slug":"42cursus"}}`))
+		case req.URL.Path == "/cursus_users/7":
+			rw.WriteHeader(http.StatusNoContent)
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCursusAdd(t *testing.T) {
+	var bodies []string
+	server := newCursusServer(t, &bodies)
+	var api ftapi.APIInterface = ftapi.New(server.URL, server.Client(), ftapi.WithRateLimiter(nil))
+	stdout := bytes.NewBufferString("")
+	addCmd := NewCursusAddCmd(&api)
+	addCmd.SetArgs([]string{"spoody", "42cursus", "--begin-at", "2021-10-04T09:00:00Z"})
+	addCmd.SetOut(stdout)
+	assert.Nil(t, addCmd.Execute())
+	assert.Equal(t, "spoody enrolled in the cursus 42cursus from 2021-10-04 09:00, cursus_user 8\n", stdout.String())
+	assert.Equal(t, []string{`POST /cursus_users {"cursus_user":{"begin_at":"2021-10-04T09:00:00Z","cursus_id":21,"user_id":37}}`}, bodies)
+
+	addCmd = NewCursusAddCmd(&api)
+	addCmd.SetArgs([]string{"spoody", "atlantis"})
+	addCmd.SilenceUsage = true
+	addCmd.SetOut(stdout)
+	addCmd.SetErr(stdout)
+	assert.EqualError(t, addCmd.Execute(), "cursus atlantis: not found")
+}
+
+func TestCursusUpdate(t *testing.T) {
+	var bodies []string
+	server := newCursusServer(t, &bodies)
+	var api ftapi.APIInterface = ftapi.New(server.URL, server.Client(), ftapi.WithRateLimiter(nil))
+	journal := newTestJournal(t)
+	api = journaledClient{api, journal}
+	stdout := bytes.NewBufferString("")
+	updateCmd := NewCursusUpdateCmd(&api)
+	updateCmd.SetArgs([]string{"spoody", "21", "--end-at", "2023-07-01T00:00:00Z", "--grade", "Member"})
+	updateCmd.SetOut(stdout)
+	assert.Nil(t, updateCmd.Execute())
+	assert.Equal(t, "Enrollment of spoody in the cursus 21 updated\n", stdout.String())
+	assert.Equal(t, []string{`PATCH /cursus_users/7 {"cursus_user":{"end_at":"2023-07-01T00:00:00Z","grade":"Member"}}`}, bodies)
+
+	entries, err := journal.Read()
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, `end_at: "2022-06-30T22:00:00Z" -> "2023-07-01T00:00:00Z", grade: "Learner" -> "Member"`, entries[0].describe())
+
+	_, _, err = executeRevert(t, api, journal, "1")
+	assert.Nil(t, err)
+	assert.Len(t, bodies, 2)
+	var reverted map[string]map[string]string
+	assert.Nil(t, json.Unmarshal([]byte(bodies[1][len("PATCH /cursus_users/7 "):]), &reverted))
+	assert.Equal(t, map[string]string{"end_at": "2022-06-30T22:00:00Z", "grade": "Learner"}, reverted["cursus_user"])
+}
+
+func TestCursusUpdateRequiresAChange(t *testing.T) {
+	var api ftapi.APIInterface = &baseMockAPI{}
+	updateCmd := NewCursusUpdateCmd(&api)
+	updateCmd.SetArgs([]string{"spoody", "21"})
+	updateCmd.SilenceUsage = true
+	updateCmd.SetOut(bytes.NewBufferString(""))
+	updateCmd.SetErr(bytes.NewBufferString(""))
+	assert.EqualError(t, updateCmd.Execute(), "at least one of --end-at, --blackholed-at or --grade is required")
+}
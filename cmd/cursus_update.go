@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"goft/pkg/ftapi"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCursusUpdateCmd creates the cursus update cmd
+func NewCursusUpdateCmd(api *ftapi.APIInterface) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "update login cursus",
+		Short: "Update the enrollment of a user in a cursus",
+		Long: `Update the end date, blackhole date or grade of the enrollment of a user in a cursus
+given by ID or slug, like 21 or 42cursus:
+  goft cursus update norminet 42cursus --end-at 2023-07-01
+
+The dates can only be changed, not removed. The change can be undone with goft revert.`,
+		Annotations: map[string]string{
+			argsAnnotation: "login",
+		},
+		ValidArgsFunction: completeArgs(api),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(2)(cmd, args); err != nil {
+				return err
+			}
+			changed := false
+			for _, flag := range []string{"end-at", "blackholed-at", "grade"} {
+				value, _ := cmd.Flags().GetString(flag)
+				if value == "" {
+					continue
+				}
+				changed = true
+				if flag == "grade" {
+					continue
+				}
+				if _, err := parseDate(value); err != nil {
+					return err
+				}
+			}
+			if !changed {
+				return errors.New("at least one of --end-at, --blackholed-at or --grade is required")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			login, err := resolveLogin(cmd.Context(), *api, args[0])
+			if err != nil {
+				return err
+			}
+			cursusID, err := resolveCursusID(cmd.Context(), *api, args[1])
+			if err != nil {
+				return err
+			}
+			cursusUser, err := findCursusUser(cmd.Context(), *api, login, cursusID)
+			if err != nil {
+				return err
+			}
+			data := ftapi.CursusUser{}
+			data.Grade, _ = cmd.Flags().GetString("grade")
+			if value, _ := cmd.Flags().GetString("end-at"); value != "" {
+				endAt, _ := parseDate(value)
+				data.EndAt = &endAt
+			}
+			if value, _ := cmd.Flags().GetString("blackholed-at"); value != "" {
+				blackholedAt, _ := parseDate(value)
+				data.BlackholedAt = &blackholedAt
+			}
+			if err := (*api).UpdateCursusUserContext(cmd.Context(), cursusUser.ID, &data); err != nil {
+				return err
+			}
+			if isDryRun(cmd) {
+				return nil
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Enrollment of %s in the cursus %s updated\n", login, args[1])
+			return nil
+		},
+	}
+	cmd.Flags().String("end-at", "", "New end of the enrollment")
+	cmd.Flags().String("blackholed-at", "", "New blackhole date")
+	cmd.Flags().String("grade", "", "New grade, like Learner or Member")
+	return cmd
+}
+
+var cursusUpdateCmd = NewCursusUpdateCmd(&API)
+
+func init() {
+	cursusCmd.AddCommand(cursusUpdateCmd)
+}
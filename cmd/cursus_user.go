@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"goft/pkg/ftapi"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// formatDate prints an optional date of a cursus user, empty when it isn't set
+func formatDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Local().Format("2006-01-02 15:04")
+}
+
+// cursusUsersTable returns the table and csv view of the enrollments of a user
+func cursusUsersTable(cursusUsers ...*ftapi.CursusUser) table {
+	t := table{Header: []string{"ID", "CURSUS", "GRADE", "LEVEL", "BEGIN", "END", "BLACKHOLED"}}
+	for _, cursusUser := range cursusUsers {
+		cursus := strconv.Itoa(cursusUser.CursusID)
+		if cursusUser.Cursus != nil {
+			cursus = cursusUser.Cursus.Slug
+		}
+		t.Rows = append(t.Rows, []string{
+			strconv.Itoa(cursusUser.ID),
+			cursus,
+			cursusUser.Grade,
+			strconv.FormatFloat(float64(cursusUser.Level), 'f', 2, 32),
+			formatDate(cursusUser.BeginAt),
+			formatDate(cursusUser.EndAt),
+			formatDate(cursusUser.BlackholedAt),
+		})
+	}
+	return t
+}
+
+// NewCursusUserCmd creates the cursus user cmd
+func NewCursusUserCmd(api *ftapi.APIInterface) *cobra.Command {
+	return &cobra.Command{
+		Use:   "user login",
+		Short: "List the cursus a user is enrolled in",
+		Annotations: map[string]string{
+			argsAnnotation: "login",
+		},
+		ValidArgsFunction: completeArgs(api),
+		Args:              cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			login, err := resolveLogin(cmd.Context(), *api, args[0])
+			if err != nil {
+				return err
+			}
+			cursusUsers, err := (*api).GetUserCursusContext(cmd.Context(), login)
+			if err != nil {
+				return err
+			}
+			t := cursusUsersTable(cursusUsers...)
+			return printOutput(cmd, output{
+				Data:  cursusUsers,
+				Table: t,
+				Text: func(w io.Writer) {
+					for _, row := range t.Rows {
+						_, _ = fmt.Fprintf(w, "%s\t%s\tlevel %s\tfrom %s", row[1], row[2], row[3], row[4])
+						if row[5] != "" {
+							_, _ = fmt.Fprintf(w, " to %s", row[5])
+						}
+						if row[6] != "" {
+							_, _ = fmt.Fprintf(w, ", blackholed at %s", row[6])
+						}
+						_, _ = fmt.Fprintln(w)
+					}
+				},
+			})
+		},
+	}
+}
+
+var cursusUserCmd = NewCursusUserCmd(&API)
+
+func init() {
+	cursusCmd.AddCommand(cursusUserCmd)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// revertedBy maps the IDs of the reverted entries to the ID of the entry reverting them
+func revertedBy(entries []historyEntry) map[int]int {
+	reverted := map[int]int{}
+	for _, entry := range entries {
+		if entry.Reverts != 0 {
+			reverted[entry.Reverts] = entry.ID
+		}
+	}
+	return reverted
+}
+
+// NewHistoryCmd creates the history cmd
+func NewHistoryCmd(journal **historyJournal) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List the recent changes made by goft",
+		Long: `List the recent changes made by goft with the current profile, from the oldest.
+
+The changes are kept in ~/.config/goft/history.jsonl, or in the directory of the profile.
+Use goft revert with the ID of a change to undo it.`,
+		Annotations: map[string]string{
+			credentialsAnnotation: "none",
+		},
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := (*journal).Read()
+			if err != nil {
+				return err
+			}
+			limit, _ := cmd.Flags().GetInt("limit")
+			reverted := revertedBy(entries)
+			if limit > 0 && len(entries) > limit {
+				entries = entries[len(entries)-limit:]
+			}
+
+			t := table{Header: []string{"ID", "TIME", "ACTION", "LOGIN", "DETAILS", "REVERTED BY"}}
+			for _, entry := range entries {
+				revertedByID := ""
+				if id, ok := reverted[entry.ID]; ok {
+					revertedByID = strconv.Itoa(id)
+				}
+				t.Rows = append(t.Rows, []string{
+					strconv.Itoa(entry.ID),
+					entry.Time.Local().Format("2006-01-02 15:04:05"),
+					entry.Action,
+					entry.Login,
+					entry.describe(),
+					revertedByID,
+				})
+			}
+			return printOutput(cmd, output{
+				Data:  entries,
+				Table: t,
+				Text: func(w io.Writer) {
+					for _, entry := range entries {
+						_, _ = fmt.Fprintf(w, "#%d %s %s %s %s", entry.ID, entry.Time.Local().Format("2006-01-02 15:04:05"), entry.Action, entry.Login, entry.describe())
+						if entry.Reverts != 0 {
+							_, _ = fmt.Fprintf(w, " (reverts #%d)", entry.Reverts)
+						}
+						if id, ok := reverted[entry.ID]; ok {
+							_, _ = fmt.Fprintf(w, " (reverted by #%d)", id)
+						}
+						_, _ = fmt.Fprintln(w)
+					}
+				},
+			})
+		},
+	}
+	cmd.Flags().IntP("limit", "n", 20, "Number of changes to list, 0 lists them all")
+	return cmd
+}
+
+var historyCmd = NewHistoryCmd(&history)
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+}
@@ -0,0 +1,479 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goft/pkg/ftapi"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Actions of the history entries
+const (
+	actionAddPoints     = "add_points"
+	actionRemovePoints  = "remove_points"
+	actionUpdateUser    = "update_user"
+	actionCreateUser    = "create_user"
+	actionSetUserImage  = "set_user_image"
+	actionCreateClose   = "create_close"
+	actionCreateFreeAgu = "create_free_past_agu"
+	actionAddToCursus   = "add_to_cursus"
+	actionUpdateCursus  = "update_cursus_user"
+	actionRequest       = "request"
+)
+
+// historyEntry is an operation goft performed on the intra, with what is needed to revert it
+type historyEntry struct {
+	ID      int       `json:"id"`
+	Time    time.Time `json:"time"`
+	Command string    `json:"command,omitempty"`
+	Action  string    `json:"action"`
+	Login   string    `json:"login,omitempty"`
+	Points  uint      `json:"points,omitempty"`
+	Kind    string    `json:"kind,omitempty"`
+	Reason  string    `json:"reason,omitempty"`
+	// CursusUserID is the enrollment created by add_to_cursus or changed by update_cursus_user
+	CursusUserID int `json:"cursus_user_id,omitempty"`
+	// Previous holds the values of the fields changed by update_user and update_cursus_user before the change
+	Previous map[string]string `json:"previous,omitempty"`
+	Changes  map[string]string `json:"changes,omitempty"`
+	// Method and Endpoint of the raw requests
+	Method   string `json:"method,omitempty"`
+	Endpoint string `json:"endpoint,omitempty"`
+	// Reverts is the ID of the entry this operation reverted
+	Reverts int `json:"reverts,omitempty"`
+}
+
+// describe summarizes what the operation changed
+func (e historyEntry) describe() string {
+	switch e.Action {
+	case actionAddPoints:
+		return fmt.Sprintf("+%d correction points: %s", e.Points, e.Reason)
+	case actionRemovePoints:
+		return fmt.Sprintf("-%d correction points: %s", e.Points, e.Reason)
+	case actionUpdateUser, actionUpdateCursus:
+		fields := make([]string, 0, len(e.Changes))
+		for field := range e.Changes {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		changes := make([]string, 0, len(fields))
+		for _, field := range fields {
+			if field == "password" {
+				changes = append(changes, "password changed")
+				continue
+			}
+			changes = append(changes, fmt.Sprintf("%s: %q -> %q", field, e.Previous[field], e.Changes[field]))
+		}
+		return strings.Join(changes, ", ")
+	case actionCreateClose:
+		return fmt.Sprintf("%s close: %s", e.Kind, e.Reason)
+	case actionCreateFreeAgu:
+		return fmt.Sprintf("%d days: %s", e.Points, e.Reason)
+	case actionAddToCursus:
+		return fmt.Sprintf("cursus %s, cursus_user %d", e.Kind, e.CursusUserID)
+	case actionRequest:
+		return e.Method + " " + e.Endpoint
+	}
+	return ""
+}
+
+// irreversibleReason explains why an action has no inverse, it is empty for the reversible actions
+func irreversibleReason(action string) string {
+	switch action {
+	case actionAddPoints, actionRemovePoints, actionUpdateUser, actionUpdateCursus:
+		return ""
+	case actionCreateUser:
+		return "users can't be deleted through the API"
+	case actionSetUserImage:
+		return "the previous image was not saved"
+	case actionCreateClose:
+		return "closes can't be deleted through the API"
+	case actionCreateFreeAgu:
+		return "AGUs can't be deleted through the API"
+	case actionAddToCursus:
+		return "cursus users can't be deleted through the API"
+	case actionRequest:
+		return "raw requests have no known inverse"
+	}
+	return "unknown action " + action
+}
+
+// historyJournal stores the operations performed by goft in a JSON lines file, one per profile
+type historyJournal struct {
+	Path string
+	// Command is the command line recorded with the entries
+	Command string
+
+	mu sync.Mutex
+}
+
+// journalCommand returns the command line recorded with the entries: the command path and its positional args.
+// The flags are left out as some of them hold secrets, like --smtp-pass
+func journalCommand(cmd *cobra.Command, args []string) string {
+	return strings.Join(append([]string{cmd.CommandPath()}, args...), " ")
+}
+
+// SetCommand sets the command line recorded with the next entries to the one of cmd
+func (j *historyJournal) SetCommand(cmd *cobra.Command, args []string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Command = journalCommand(cmd, args)
+}
+
+// newHistoryJournal creates a journal stored at path
+func newHistoryJournal(path string, command string) *historyJournal {
+	return &historyJournal{Path: path, Command: command}
+}
+
+// Read returns the entries of the journal from the oldest, a missing journal is empty
+func (j *historyJournal) Read() ([]historyEntry, error) {
+	file, err := os.Open(j.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var entries []historyEntry
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var entry historyEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", j.Path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// Append numbers entry after the last one and adds it to the journal
+func (j *historyJournal) Append(entry historyEntry) (historyEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	entries, err := j.Read()
+	if err != nil {
+		return entry, err
+	}
+	entry.ID = 1
+	if len(entries) > 0 {
+		entry.ID = entries[len(entries)-1].ID + 1
+	}
+	entry.Time = time.Now()
+	entry.Command = j.Command
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return entry, err
+	}
+	if err := os.MkdirAll(filepath.Dir(j.Path), 0700); err != nil {
+		return entry, err
+	}
+	file, err := os.OpenFile(j.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return entry, err
+	}
+	_, err = file.Write(append(data, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return entry, err
+}
+
+// revertKey is the context key of the ID of the entry being reverted
+type revertKey struct{}
+
+// withRevert marks the operations sent with the returned context as the revert of the entry id
+func withRevert(ctx context.Context, id int) context.Context {
+	return context.WithValue(ctx, revertKey{}, id)
+}
+
+// journaledClient records the successful operations of the wrapped client in a journal
+type journaledClient struct {
+	ftapi.APIInterface
+	journal *historyJournal
+}
+
+// record adds entry to the journal, failing to record doesn't fail the operation which was already done
+func (c journaledClient) record(ctx context.Context, entry historyEntry) {
+	entry.Reverts, _ = ctx.Value(revertKey{}).(int)
+	if _, err := c.journal.Append(entry); err != nil {
+		_, _ = fmt.Fprintln(os.Stderr, "history:", err)
+	}
+}
+
+// CreateUser creates the user and records it
+func (c journaledClient) CreateUser(user *ftapi.User, campusID int) error {
+	return c.CreateUserContext(context.Background(), user, campusID)
+}
+
+// CreateUserContext is the same as CreateUser but uses ctx for the underlying requests
+func (c journaledClient) CreateUserContext(ctx context.Context, user *ftapi.User, campusID int) error {
+	if err := c.APIInterface.CreateUserContext(ctx, user, campusID); err != nil {
+		return err
+	}
+	c.record(ctx, historyEntry{Action: actionCreateUser, Login: user.Login})
+	return nil
+}
+
+// SetUserImage sets the image and records it
+func (c journaledClient) SetUserImage(login string, img *os.File) error {
+	return c.SetUserImageContext(context.Background(), login, img)
+}
+
+// SetUserImageContext is the same as SetUserImage but uses ctx for the underlying requests
+func (c journaledClient) SetUserImageContext(ctx context.Context, login string, img *os.File) error {
+	if err := c.APIInterface.SetUserImageContext(ctx, login, img); err != nil {
+		return err
+	}
+	c.record(ctx, historyEntry{Action: actionSetUserImage, Login: login})
+	return nil
+}
+
+// CreateClose creates the close and records it
+func (c journaledClient) CreateClose(close *ftapi.Close) error {
+	return c.CreateCloseContext(context.Background(), close)
+}
+
+// CreateCloseContext is the same as CreateClose but uses ctx for the underlying requests
+func (c journaledClient) CreateCloseContext(ctx context.Context, close *ftapi.Close) error {
+	if err := c.APIInterface.CreateCloseContext(ctx, close); err != nil {
+		return err
+	}
+	entry := historyEntry{Action: actionCreateClose, Kind: close.Kind, Reason: close.Reason}
+	if close.User != nil {
+		entry.Login = close.User.Login
+	}
+	c.record(ctx, entry)
+	return nil
+}
+
+// UpdateUser updates the user and records the previous values of the changed fields
+func (c journaledClient) UpdateUser(login string, data *ftapi.User) error {
+	return c.UpdateUserContext(context.Background(), login, data)
+}
+
+// UpdateUserContext is the same as UpdateUser but uses ctx for the underlying requests
+func (c journaledClient) UpdateUserContext(ctx context.Context, login string, data *ftapi.User) error {
+	current, err := c.APIInterface.GetUserByLoginContext(ctx, login)
+	if err != nil {
+		return err
+	}
+	if err := c.APIInterface.UpdateUserContext(ctx, login, data); err != nil {
+		return err
+	}
+	entry := historyEntry{Action: actionUpdateUser, Login: login, Previous: map[string]string{}, Changes: map[string]string{}}
+	previous := userFields(current)
+	for field, value := range userFields(data) {
+		if value != "" {
+			entry.Changes[field] = value
+			entry.Previous[field] = previous[field]
+		}
+	}
+	// The password is neither readable nor worth keeping
+	if data.Password != "" {
+		entry.Changes["password"] = ""
+	}
+	c.record(ctx, entry)
+	return nil
+}
+
+// userFields returns the fields of user that UpdateUser sends, except the password
+func userFields(user *ftapi.User) map[string]string {
+	return map[string]string{
+		"email":      user.Email,
+		"first_name": user.FirstName,
+		"last_name":  user.LastName,
+		"kind":       user.Kind,
+	}
+}
+
+// AddCorrectionPoints adds the points and records it
+func (c journaledClient) AddCorrectionPoints(login string, points uint, reason string) error {
+	return c.AddCorrectionPointsContext(context.Background(), login, points, reason)
+}
+
+// AddCorrectionPointsContext is the same as AddCorrectionPoints but uses ctx for the underlying requests
+func (c journaledClient) AddCorrectionPointsContext(ctx context.Context, login string, points uint, reason string) error {
+	if err := c.APIInterface.AddCorrectionPointsContext(ctx, login, points, reason); err != nil {
+		return err
+	}
+	c.record(ctx, historyEntry{Action: actionAddPoints, Login: login, Points: points, Reason: reason})
+	return nil
+}
+
+// RemoveCorrectionPoints removes the points and records it
+func (c journaledClient) RemoveCorrectionPoints(login string, points uint, reason string) error {
+	return c.RemoveCorrectionPointsContext(context.Background(), login, points, reason)
+}
+
+// RemoveCorrectionPointsContext is the same as RemoveCorrectionPoints but uses ctx for the underlying requests
+func (c journaledClient) RemoveCorrectionPointsContext(ctx context.Context, login string, points uint, reason string) error {
+	if err := c.APIInterface.RemoveCorrectionPointsContext(ctx, login, points, reason); err != nil {
+		return err
+	}
+	c.record(ctx, historyEntry{Action: actionRemovePoints, Login: login, Points: points, Reason: reason})
+	return nil
+}
+
+// CreateFreePastAgu creates the AGU and records it
+func (c journaledClient) CreateFreePastAgu(login string, duration int, reason string) error {
+	return c.CreateFreePastAguContext(context.Background(), login, duration, reason)
+}
+
+// CreateFreePastAguContext is the same as CreateFreePastAgu but uses ctx for the underlying requests
+func (c journaledClient) CreateFreePastAguContext(ctx context.Context, login string, duration int, reason string) error {
+	if err := c.APIInterface.CreateFreePastAguContext(ctx, login, duration, reason); err != nil {
+		return err
+	}
+	c.record(ctx, historyEntry{Action: actionCreateFreeAgu, Login: login, Points: uint(duration), Reason: reason})
+	return nil
+}
+
+// AddUserToCursus enrolls the user and records it
+func (c journaledClient) AddUserToCursus(userID int, cursusID int, beginAt time.Time, endAt *time.Time) (*ftapi.CursusUser, error) {
+	return c.AddUserToCursusContext(context.Background(), userID, cursusID, beginAt, endAt)
+}
+
+// AddUserToCursusContext is the same as AddUserToCursus but uses ctx for the underlying requests
+func (c journaledClient) AddUserToCursusContext(ctx context.Context, userID int, cursusID int, beginAt time.Time, endAt *time.Time) (*ftapi.CursusUser, error) {
+	cursusUser, err := c.APIInterface.AddUserToCursusContext(ctx, userID, cursusID, beginAt, endAt)
+	if err != nil {
+		return nil, err
+	}
+	entry := historyEntry{Action: actionAddToCursus, Kind: strconv.Itoa(cursusID), CursusUserID: cursusUser.ID}
+	if cursusUser.User != nil {
+		entry.Login = cursusUser.User.Login
+	}
+	if cursusUser.Cursus != nil {
+		entry.Kind = cursusUser.Cursus.Slug
+	}
+	c.record(ctx, entry)
+	return cursusUser, nil
+}
+
+// UpdateCursusUser updates the enrollment and records the previous values of the changed fields
+func (c journaledClient) UpdateCursusUser(id int, data *ftapi.CursusUser) error {
+	return c.UpdateCursusUserContext(context.Background(), id, data)
+}
+
+// UpdateCursusUserContext is the same as UpdateCursusUser but uses ctx for the underlying requests
+func (c journaledClient) UpdateCursusUserContext(ctx context.Context, id int, data *ftapi.CursusUser) error {
+	current, err := c.APIInterface.GetCursusUserContext(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := c.APIInterface.UpdateCursusUserContext(ctx, id, data); err != nil {
+		return err
+	}
+	entry := historyEntry{Action: actionUpdateCursus, CursusUserID: id, Previous: map[string]string{}, Changes: map[string]string{}}
+	if current.User != nil {
+		entry.Login = current.User.Login
+	}
+	if current.Cursus != nil {
+		entry.Kind = current.Cursus.Slug
+	}
+	previous := cursusUserFields(current)
+	for field, value := range cursusUserFields(data) {
+		if value != "" {
+			entry.Changes[field] = value
+			entry.Previous[field] = previous[field]
+		}
+	}
+	c.record(ctx, entry)
+	return nil
+}
+
+// cursusUserFields returns the fields of cursusUser that UpdateCursusUser sends, the dates in RFC 3339
+func cursusUserFields(cursusUser *ftapi.CursusUser) map[string]string {
+	fields := map[string]string{
+		"end_at":        "",
+		"blackholed_at": "",
+		"grade":         cursusUser.Grade,
+	}
+	if cursusUser.EndAt != nil {
+		fields["end_at"] = cursusUser.EndAt.Format(time.RFC3339)
+	}
+	if cursusUser.BlackholedAt != nil {
+		fields["blackholed_at"] = cursusUser.BlackholedAt.Format(time.RFC3339)
+	}
+	return fields
+}
+
+// recordRequest records the raw requests that succeeded
+func (c journaledClient) recordRequest(ctx context.Context, method string, url string, resp *http.Response, err error) (*http.Response, error) {
+	if err == nil && resp != nil && resp.StatusCode < 400 {
+		c.record(ctx, historyEntry{Action: actionRequest, Method: method, Endpoint: url})
+	}
+	return resp, err
+}
+
+// Post sends the request and records it
+func (c journaledClient) Post(url string, contentType string, body io.Reader) (*http.Response, error) {
+	return c.PostContext(context.Background(), url, contentType, body)
+}
+
+// PostContext is the same as Post but uses ctx for the request
+func (c journaledClient) PostContext(ctx context.Context, url string, contentType string, body io.Reader) (*http.Response, error) {
+	resp, err := c.APIInterface.PostContext(ctx, url, contentType, body)
+	return c.recordRequest(ctx, http.MethodPost, url, resp, err)
+}
+
+// PostJSON sends the request and records it
+func (c journaledClient) PostJSON(url string, data interface{}) (*http.Response, error) {
+	return c.PostJSONContext(context.Background(), url, data)
+}
+
+// PostJSONContext is the same as PostJSON but uses ctx for the request
+func (c journaledClient) PostJSONContext(ctx context.Context, url string, data interface{}) (*http.Response, error) {
+	resp, err := c.APIInterface.PostJSONContext(ctx, url, data)
+	return c.recordRequest(ctx, http.MethodPost, url, resp, err)
+}
+
+// Patch sends the request and records it
+func (c journaledClient) Patch(url string, contentType string, body io.Reader) (*http.Response, error) {
+	return c.PatchContext(context.Background(), url, contentType, body)
+}
+
+// PatchContext is the same as Patch but uses ctx for the request
+func (c journaledClient) PatchContext(ctx context.Context, url string, contentType string, body io.Reader) (*http.Response, error) {
+	resp, err := c.APIInterface.PatchContext(ctx, url, contentType, body)
+	return c.recordRequest(ctx, http.MethodPatch, url, resp, err)
+}
+
+// PatchJSON sends the request and records it
+func (c journaledClient) PatchJSON(url string, data interface{}) (*http.Response, error) {
+	return c.PatchJSONContext(context.Background(), url, data)
+}
+
+// PatchJSONContext is the same as PatchJSON but uses ctx for the request
+func (c journaledClient) PatchJSONContext(ctx context.Context, url string, data interface{}) (*http.Response, error) {
+	resp, err := c.APIInterface.PatchJSONContext(ctx, url, data)
+	return c.recordRequest(ctx, http.MethodPatch, url, resp, err)
+}
+
+// Delete sends the request and records it
+func (c journaledClient) Delete(url string, contentType string, body io.Reader) (*http.Response, error) {
+	return c.DeleteContext(context.Background(), url, contentType, body)
+}
+
+// DeleteContext is the same as Delete but uses ctx for the request
+func (c journaledClient) DeleteContext(ctx context.Context, url string, contentType string, body io.Reader) (*http.Response, error) {
+	resp, err := c.APIInterface.DeleteContext(ctx, url, contentType, body)
+	return c.recordRequest(ctx, http.MethodDelete, url, resp, err)
+}
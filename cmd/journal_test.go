@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"goft/pkg/ftapi"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/sethvargo/go-password/password"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+type journalMockAPI struct {
+	baseMockAPI
+	added   map[string]uint
+	removed map[string]uint
+	updated map[string]ftapi.User
+}
+
+func newJournalMockAPI() *journalMockAPI {
+	return &journalMockAPI{added: map[string]uint{}, removed: map[string]uint{}, updated: map[string]ftapi.User{}}
+}
+
+func (m *journalMockAPI) GetUserByLoginContext(ctx context.Context, login string) (*ftapi.User, error) {
+	return &ftapi.User{Login: login, Email: "old@1337.ma", FirstName: "Mehdi", LastName: "Bounya", Kind: "student"}, nil
+}
+
+func (m *journalMockAPI) UpdateUserContext(ctx context.Context, login string, data *ftapi.User) error {
+	m.updated[login] = *data
+	return nil
+}
+
+func (m *journalMockAPI) AddCorrectionPointsContext(ctx context.Context, login string, points uint, reason string) error {
+	m.added[login] += points
+	return nil
+}
+
+func (m *journalMockAPI) RemoveCorrectionPointsContext(ctx context.Context, login string, points uint, reason string) error {
+	m.removed[login] += points
+	return nil
+}
+
+func (m *journalMockAPI) CreateCloseContext(ctx context.Context, close *ftapi.Close) error {
+	return nil
+}
+
+func newTestJournal(t *testing.T) *historyJournal {
+	return newHistoryJournal(filepath.Join(t.TempDir(), "history.jsonl"), "users add-points spoody 50 typo")
+}
+
+func TestJournaledClient(t *testing.T) {
+	journal := newTestJournal(t)
+	client := journaledClient{newJournalMockAPI(), journal}
+
+	assert.Nil(t, client.AddCorrectionPoints("spoody", 50, "typo"))
+	assert.Nil(t, client.UpdateUser("spoody", &ftapi.User{Email: "new@1337.ma", Password: "hunter2"}))
+	assert.Nil(t, client.CreateClose(&ftapi.Close{Kind: "other", Reason: "left", User: &ftapi.User{Login: "spoody"}}))
+
+	entries, err := journal.Read()
+	assert.Nil(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, []int{1, 2, 3}, []int{entries[0].ID, entries[1].ID, entries[2].ID})
+	assert.Equal(t, "users add-points spoody 50 typo", entries[0].Command)
+	assert.Equal(t, "+50 correction points: typo", entries[0].describe())
+	assert.Equal(t, actionUpdateUser, entries[1].Action)
+	assert.Equal(t, map[string]string{"email": "old@1337.ma"}, entries[1].Previous)
+	assert.Equal(t, map[string]string{"email": "new@1337.ma", "password": ""}, entries[1].Changes)
+	assert.Equal(t, `email: "old@1337.ma" -> "new@1337.ma", password changed`, entries[1].describe())
+	assert.Equal(t, "other close: left", entries[2].describe())
+}
+
+func TestJournalCommandLeavesOutSecrets(t *testing.T) {
+	journal := newHistoryJournal(filepath.Join(t.TempDir(), "history.jsonl"), "")
+	var api ftapi.APIInterface = journaledClient{newJournalMockAPI(), journal}
+	// Set like the root command does before running its subcommands
+	parent := &cobra.Command{
+		Use: "goft",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			journal.SetCommand(cmd, args)
+		},
+	}
+	parent.AddCommand(NewResetPasswdCmd(&api, password.NewMockGenerator("s3cr3tpassw0rd", nil), (&mockSMTP{}).dial))
+	parent.SetArgs([]string{"reset-passwd", "spoody", "--smtp-host", "smtp.test", "--smtp-user", "goft",
+		"--smtp-pass", "smtp-secret", "--from-email", "noreply@1337.ma"})
+	parent.SetOut(bytes.NewBufferString(""))
+	assert.Nil(t, parent.Execute())
+
+	data, err := ioutil.ReadFile(journal.Path)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(data), "smtp-secret")
+	assert.NotContains(t, string(data), "s3cr3tpassw0rd")
+	entries, err := journal.Read()
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "goft reset-passwd spoody", entries[0].Command)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"goft/pkg/ftapi"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// formatHours prints a duration in hours and minutes, like 3h05
+func formatHours(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	return fmt.Sprintf("%dh%02d", minutes/60, minutes%60)
+}
+
+// locationsTable returns the table and csv view of sessions, the active ones have no end
+func locationsTable(now time.Time, locations ...*ftapi.Location) table {
+	t := table{Header: []string{"LOGIN", "HOST", "BEGIN", "END", "DURATION"}}
+	for _, location := range locations {
+		login := ""
+		if location.User != nil {
+			login = location.User.Login
+		}
+		t.Rows = append(t.Rows, []string{
+			login,
+			location.Host,
+			location.BeginAt.Local().Format("2006-01-02 15:04"),
+			formatDate(location.EndAt),
+			formatHours(location.Duration(now)),
+		})
+	}
+	return t
+}
+
+// writeLocationsText prints one session per line, hideLogin leaves out the login when they are all the same user's
+func writeLocationsText(w io.Writer, now time.Time, hideLogin bool, locations []*ftapi.Location) {
+	for _, location := range locations {
+		if !hideLogin && location.User != nil {
+			_, _ = fmt.Fprintf(w, "%s\t", location.User.Login)
+		}
+		end := "now"
+		if location.EndAt != nil {
+			end = location.EndAt.Local().Format("15:04")
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s - %s (%s)\n", location.Host, location.BeginAt.Local().Format("2006-01-02 15:04"), end, formatHours(location.Duration(now)))
+	}
+}
+
+// printLocations prints the sessions in the format selected with --output
+func printLocations(cmd *cobra.Command, hideLogin bool, locations []*ftapi.Location) error {
+	now := time.Now()
+	return printOutput(cmd, output{
+		Data:  locations,
+		Table: locationsTable(now, locations...),
+		Text: func(w io.Writer) {
+			writeLocationsText(w, now, hideLogin, locations)
+		},
+	})
+}
+
+// sinceParams returns the params listing the sessions begun after the --since flag, none when it isn't set
+func sinceParams(cmd *cobra.Command, now time.Time) (url.Values, error) {
+	params := url.Values{"sort": {"-begin_at"}}
+	value, _ := cmd.Flags().GetString("since")
+	if value == "" {
+		return params, nil
+	}
+	since, err := parseSince(value, now)
+	if err != nil {
+		return nil, err
+	}
+	params.Set("range[begin_at]", since.UTC().Format(time.RFC3339)+","+now.UTC().Format(time.RFC3339))
+	return params, nil
+}
+
+// NewLocationsCmd creates the locations cmd
+func NewLocationsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "locations",
+		Short: "Find who is logged in where",
+		Long: `Find who is logged in where, from the sessions on the campus computers.
+
+--since takes a duration like 24h or 7d, or a date like 2006-01-02.`,
+	}
+}
+
+var locationsCmd = NewLocationsCmd()
+
+func init() {
+	rootCmd.AddCommand(locationsCmd)
+}
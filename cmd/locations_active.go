@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"goft/pkg/ftapi"
+	"net/url"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// NewLocationsActiveCmd creates the locations active cmd
+func NewLocationsActiveCmd(api *ftapi.APIInterface) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "active",
+		Short: "List who is logged in on the campus computers",
+		Annotations: map[string]string{
+			tokenAnnotation: "user",
+		},
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(0)(cmd, args); err != nil {
+				return err
+			}
+			return checkCampusFlag(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			campusID, err := campusFlagID(cmd, *api)
+			if err != nil {
+				return err
+			}
+			var locations []*ftapi.Location
+			params := url.Values{"filter[active]": {"true"}}
+			err = (*api).ListCampusLocationsContext(cmd.Context(), campusID, params).PageSize(ftapi.MaxPageSize).All(&locations)
+			if err != nil {
+				return err
+			}
+			sort.SliceStable(locations, func(i, j int) bool { return locations[i].Host < locations[j].Host })
+			return printLocations(cmd, false, locations)
+		},
+	}
+	addCampusFlag(cmd, api)
+	return cmd
+}
+
+var locationsActiveCmd = NewLocationsActiveCmd(&API)
+
+func init() {
+	locationsCmd.AddCommand(locationsActiveCmd)
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"goft/pkg/ftapi"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// NewLocationsHostCmd creates the locations host cmd
+func NewLocationsHostCmd(api *ftapi.APIInterface) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "host hostname",
+		Short: "Show who is logged in on a computer",
+		Long: `Show who is logged in on a computer of the campus, like e1r2p3.
+
+With --since, the sessions begun since then are listed, the last first:
+  goft locations host e1r2p3 --since 2021-11-02`,
+		Annotations: map[string]string{
+			tokenAnnotation: "user",
+		},
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return err
+			}
+			return checkCampusFlag(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			campusID, err := campusFlagID(cmd, *api)
+			if err != nil {
+				return err
+			}
+			params, err := sinceParams(cmd, time.Now())
+			if err != nil {
+				return err
+			}
+			params.Set("filter[host]", args[0])
+			if !cmd.Flags().Changed("since") {
+				params.Set("filter[active]", "true")
+			}
+			var locations []*ftapi.Location
+			err = (*api).ListCampusLocationsContext(cmd.Context(), campusID, params).PageSize(ftapi.MaxPageSize).All(&locations)
+			if err != nil {
+				return err
+			}
+			if len(locations) == 0 && outputFormat(cmd) == "" {
+				if since, _ := cmd.Flags().GetString("since"); since != "" {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Nobody logged in on %s since %s\n", args[0], since)
+					return nil
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Nobody is logged in on %s\n", args[0])
+				return nil
+			}
+			return printLocations(cmd, false, locations)
+		},
+	}
+	addCampusFlag(cmd, api)
+	cmd.Flags().String("since", "", "List the sessions begun since then instead of the current one, like 24h, 7d or 2006-01-02")
+	return cmd
+}
+
+var locationsHostCmd = NewLocationsHostCmd(&API)
+
+func init() {
+	locationsCmd.AddCommand(locationsHostCmd)
+}
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goft/pkg/ftapi"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// seatWidth is the width of a seat on the map, the logins are at most 8 characters
+const seatWidth = 9
+
+// clearScreen moves the cursor home and clears the terminal before each refresh
+const clearScreen = "\033[H\033[2J"
+
+// hostPattern matches the hostnames of the campus computers, like e1r2p3 for the post 3 of the row 2 of the cluster e1
+var hostPattern = regexp.MustCompile(`^(.+?)r(\d+)p(\d+)$`)
+
+// clusterLayout describes the rows and posts of a cluster
+type clusterLayout struct {
+	Name  string `yaml:"name"`
+	Rows  int    `yaml:"rows"`
+	Posts int    `yaml:"posts"`
+	// RowPosts overrides Posts for the rows with a different number of posts
+	RowPosts map[int]int `yaml:"row_posts"`
+}
+
+// postsOf returns the number of posts of a row
+func (c clusterLayout) postsOf(row int) int {
+	if posts, ok := c.RowPosts[row]; ok {
+		return posts
+	}
+	return c.Posts
+}
+
+// seats returns the number of posts of the cluster
+func (c clusterLayout) seats() int {
+	seats := 0
+	for row := 1; row <= c.Rows; row++ {
+		seats += c.postsOf(row)
+	}
+	return seats
+}
+
+// mapLayout is the layout file of locations map, the clusters of each campus by ID
+type mapLayout struct {
+	Campuses map[int][]clusterLayout `yaml:"campuses"`
+}
+
+// readMapLayout reads the layout file at path
+func readMapLayout(path string) (*mapLayout, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var layout mapLayout
+	if err := yaml.Unmarshal(data, &layout); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &layout, nil
+}
+
+// seat is the position of a computer in the clusters
+type seat struct {
+	Cluster string
+	Row     int
+	Post    int
+}
+
+// parseHost returns the seat of a hostname like e1r2p3 or e1r2p3.1337.ma, rows and posts start at 1
+func parseHost(host string) (seat, bool) {
+	host = strings.SplitN(host, ".", 2)[0]
+	match := hostPattern.FindStringSubmatch(host)
+	if match == nil {
+		return seat{}, false
+	}
+	row, _ := strconv.Atoi(match[2])
+	post, _ := strconv.Atoi(match[3])
+	if row < 1 || post < 1 {
+		return seat{}, false
+	}
+	return seat{Cluster: match[1], Row: row, Post: post}, true
+}
+
+// inferClusters builds the layout of the clusters seen in locations, as big as their furthest rows and posts
+func inferClusters(locations []*ftapi.Location) []clusterLayout {
+	byName := map[string]*clusterLayout{}
+	for _, location := range locations {
+		s, ok := parseHost(location.Host)
+		if !ok {
+			continue
+		}
+		cluster, ok := byName[s.Cluster]
+		if !ok {
+			cluster = &clusterLayout{Name: s.Cluster}
+			byName[s.Cluster] = cluster
+		}
+		if s.Row > cluster.Rows {
+			cluster.Rows = s.Row
+		}
+		if s.Post > cluster.Posts {
+			cluster.Posts = s.Post
+		}
+	}
+	clusters := make([]clusterLayout, 0, len(byName))
+	for _, cluster := range byName {
+		clusters = append(clusters, *cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Name < clusters[j].Name })
+	return clusters
+}
+
+// renderClusterMap draws every cluster with the logins of the occupied seats,
+// the sessions on hosts outside of the clusters are listed after them
+func renderClusterMap(w io.Writer, clusters []clusterLayout, locations []*ftapi.Location, ascii bool) {
+	free, rule := "·", "─"
+	if ascii {
+		free, rule = ".", "-"
+	}
+	occupied := map[seat]string{}
+	var elsewhere []string
+	for _, location := range locations {
+		login := ""
+		if location.User != nil {
+			login = location.User.Login
+		}
+		s, ok := parseHost(location.Host)
+		if ok {
+			for _, cluster := range clusters {
+				if cluster.Name == s.Cluster && s.Row <= cluster.Rows && s.Post <= cluster.postsOf(s.Row) {
+					occupied[s] = login
+					break
+				}
+			}
+		}
+		if _, ok := occupied[s]; !ok {
+			elsewhere = append(elsewhere, fmt.Sprintf("%s (%s)", login, location.Host))
+		}
+	}
+
+	for i, cluster := range clusters {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		taken, maxPosts := 0, 0
+		for row := 1; row <= cluster.Rows; row++ {
+			if posts := cluster.postsOf(row); posts > maxPosts {
+				maxPosts = posts
+			}
+			for post := 1; post <= cluster.postsOf(row); post++ {
+				if _, ok := occupied[seat{cluster.Name, row, post}]; ok {
+					taken++
+				}
+			}
+		}
+		_, _ = fmt.Fprintf(w, "%s  %d/%d\n", cluster.Name, taken, cluster.seats())
+		_, _ = fmt.Fprintln(w, strings.Repeat(rule, 4+maxPosts*seatWidth))
+		var header strings.Builder
+		header.WriteString("    ")
+		for post := 1; post <= maxPosts; post++ {
+			header.WriteString(fmt.Sprintf("%-*s", seatWidth, "p"+strconv.Itoa(post)))
+		}
+		_, _ = fmt.Fprintln(w, strings.TrimRight(header.String(), " "))
+		for row := 1; row <= cluster.Rows; row++ {
+			var line strings.Builder
+			line.WriteString(fmt.Sprintf("%-4s", "r"+strconv.Itoa(row)))
+			for post := 1; post <= cluster.postsOf(row); post++ {
+				cell, ok := occupied[seat{cluster.Name, row, post}]
+				if !ok {
+					cell = free
+				}
+				// Cut by runes, a multi-byte login must keep its padding aligned
+				runes := []rune(cell)
+				if len(runes) >= seatWidth {
+					runes = runes[:seatWidth-1]
+				}
+				line.WriteString(string(runes) + strings.Repeat(" ", seatWidth-len(runes)))
+			}
+			_, _ = fmt.Fprintln(w, strings.TrimRight(line.String(), " "))
+		}
+	}
+	if len(elsewhere) > 0 {
+		sort.Strings(elsewhere)
+		if len(clusters) > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintf(w, "Elsewhere: %s\n", strings.Join(elsewhere, ", "))
+	}
+}
+
+// drawLocationsMap fetches the active sessions of the campus and draws its clusters, from layout when it has them
+func drawLocationsMap(ctx context.Context, w io.Writer, api ftapi.APIInterface, campusID int, layout *mapLayout, ascii bool) error {
+	var locations []*ftapi.Location
+	params := url.Values{"filter[active]": {"true"}}
+	err := api.ListCampusLocationsContext(ctx, campusID, params).PageSize(ftapi.MaxPageSize).All(&locations)
+	if err != nil {
+		return err
+	}
+	var clusters []clusterLayout
+	if layout != nil {
+		clusters = layout.Campuses[campusID]
+	}
+	if len(clusters) == 0 {
+		clusters = inferClusters(locations)
+	}
+	renderClusterMap(w, clusters, locations, ascii)
+	return nil
+}
+
+// NewLocationsMapCmd creates the locations map cmd
+func NewLocationsMapCmd(api *ftapi.APIInterface) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "map",
+		Short: "Draw the clusters of the campus with who is logged in where",
+		Long: `Draw the clusters of the campus with the login of the users on the occupied seats,
+from hostnames like e1r2p3 for the post 3 of the row 2 of the cluster e1.
+
+The rows and posts of the clusters are read from the layout file given with --layout
+or the locations_layout setting of the config file, see layout.example.yml.
+Without it, the clusters are as big as their furthest occupied seats.
+
+With --refresh, the map is redrawn every N seconds until interrupted, like on a wall screen during exams:
+  goft locations map --campus benguerir --refresh 30`,
+		Annotations: map[string]string{
+			tokenAnnotation: "user",
+		},
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(0)(cmd, args); err != nil {
+				return err
+			}
+			if refresh, _ := cmd.Flags().GetInt("refresh"); refresh < 0 {
+				return errors.New("--refresh must be a number of seconds, or 0 to draw the map once")
+			}
+			return checkCampusFlag(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			campusID, err := campusFlagID(cmd, *api)
+			if err != nil {
+				return err
+			}
+			var layout *mapLayout
+			if path := stringSetting(cmd, "layout", "locations_layout"); path != "" {
+				layout, err = readMapLayout(path)
+				if err != nil {
+					return err
+				}
+			}
+			ascii, _ := cmd.Flags().GetBool("ascii")
+			refresh, _ := cmd.Flags().GetInt("refresh")
+			out := cmd.OutOrStdout()
+			if refresh == 0 {
+				return drawLocationsMap(cmd.Context(), out, *api, campusID, layout, ascii)
+			}
+			for {
+				// The map is drawn to a buffer first so the screen isn't blank while fetching
+				var frame strings.Builder
+				if err := drawLocationsMap(cmd.Context(), &frame, *api, campusID, layout, ascii); err != nil {
+					if cmd.Context().Err() != nil {
+						return nil
+					}
+					// A wall screen keeps the last map and retries on the next refresh
+					_, _ = fmt.Fprintf(out, "\nFailed to refresh at %s: %s\n", time.Now().Format("15:04:05"), err)
+				} else {
+					_, _ = fmt.Fprintf(out, "%s%s\nUpdated at %s, every %ds\n", clearScreen, frame.String(), time.Now().Format("15:04:05"), refresh)
+				}
+				select {
+				case <-cmd.Context().Done():
+					return nil
+				case <-time.After(time.Duration(refresh) * time.Second):
+				}
+			}
+		},
+	}
+	addCampusFlag(cmd, api)
+	cmd.Flags().String("layout", "", "Layout file describing the rows and posts of the clusters of each campus")
+	cmd.Flags().Int("refresh", 0, "Redraw the map every N seconds, 0 draws it once")
+	cmd.Flags().Bool("ascii", false, "Only use ASCII characters")
+	return cmd
+}
+
+var locationsMapCmd = NewLocationsMapCmd(&API)
+
+func init() {
+	locationsCmd.AddCommand(locationsMapCmd)
+}
@@ -0,0 +1,260 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"goft/pkg/ftapi"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHost(t *testing.T) {
+	tests := []struct {
+		host string
+		seat seat
+		ok   bool
+	}{
+		{"e1r2p3", seat{"e1", 2, 3}, true},
+		{"e1r2p3.1337.ma", seat{"e1", 2, 3}, true},
+		{"e12r10p23", seat{"e12", 10, 23}, true},
+		{"bocal-r1p2", seat{"bocal-", 1, 2}, true},
+		{"e1r0p3", seat{}, false},
+		{"e1r2p0", seat{}, false},
+		{"r2p3", seat{}, false},
+		{"e1r2", seat{}, false},
+		{"e1r2p3x", seat{}, false},
+		{"staff-mac.1337.ma", seat{}, false},
+		{"", seat{}, false},
+	}
+	for _, test := range tests {
+		s, ok := parseHost(test.host)
+		assert.Equal(t, test.ok, ok, test.host)
+		assert.Equal(t, test.seat, s, test.host)
+	}
+}
+
+func locationAt(host string, login string) *ftapi.Location {
+	return &ftapi.Location{Host: host, User: &ftapi.User{Login: login}}
+}
+
+func TestInferClusters(t *testing.T) {
+	tests := []struct {
+		name      string
+		locations []*ftapi.Location
+		clusters  []clusterLayout
+	}{
+		{
+			name:      "no locations",
+			locations: nil,
+			clusters:  []clusterLayout{},
+		},
+		{
+			name: "furthest rows and posts",
+			locations: []*ftapi.Location{
+				locationAt("e2r1p7", "norminet"),
+				locationAt("e1r4p2.1337.ma", "spoody"),
+				locationAt("e1r2p5", "mbounya"),
+			},
+			clusters: []clusterLayout{
+				{Name: "e1", Rows: 4, Posts: 5},
+				{Name: "e2", Rows: 1, Posts: 7},
+			},
+		},
+		{
+			name: "hosts outside of the clusters are ignored",
+			locations: []*ftapi.Location{
+				locationAt("staff-mac", "norminet"),
+				locationAt("e1r0p9", "mbounya"),
+				locationAt("e1r1p1", "spoody"),
+			},
+			clusters: []clusterLayout{
+				{Name: "e1", Rows: 1, Posts: 1},
+			},
+		},
+	}
+	for _, test := range tests {
+		assert.Equal(t, test.clusters, inferClusters(test.locations), test.name)
+	}
+}
+
+func TestRenderClusterMap(t *testing.T) {
+	tests := []struct {
+		name      string
+		clusters  []clusterLayout
+		locations []*ftapi.Location
+		ascii     bool
+		expected  string
+	}{
+		{
+			name:     "free seats",
+			clusters: []clusterLayout{{Name: "e1", Rows: 2, Posts: 2}},
+			expected: "e1  0/4\n" +
+				"──────────────────────\n" +
+				"    p1       p2\n" +
+				"r1  ·        ·\n" +
+				"r2  ·        ·\n",
+		},
+		{
+			name:     "ascii",
+			clusters: []clusterLayout{{Name: "e1", Rows: 2, Posts: 2}},
+			locations: []*ftapi.Location{
+				locationAt("e1r1p2", "spoody"),
+			},
+			ascii: true,
+			expected: "e1  1/4\n" +
+				"----------------------\n" +
+				"    p1       p2\n" +
+				"r1  .        spoody\n" +
+				"r2  .        .\n",
+		},
+		{
+			name: "row posts",
+			clusters: []clusterLayout{
+				{Name: "e1", Rows: 1, Posts: 1},
+				{Name: "e2", Rows: 3, Posts: 3, RowPosts: map[int]int{2: 1, 3: 2}},
+			},
+			locations: []*ftapi.Location{
+				locationAt("e2r2p1", "spoody"),
+				locationAt("e2r3p2", "norminet"),
+			},
+			ascii: true,
+			expected: "e1  0/1\n" +
+				"-------------\n" +
+				"    p1\n" +
+				"r1  .\n" +
+				"\n" +
+				"e2  2/6\n" +
+				"-------------------------------\n" +
+				"    p1       p2       p3\n" +
+				"r1  .        .        .\n" +
+				"r2  spoody\n" +
+				"r3  .        norminet\n",
+		},
+		{
+			name:     "logins are cut to the seat width",
+			clusters: []clusterLayout{{Name: "e1", Rows: 1, Posts: 3}},
+			locations: []*ftapi.Location{
+				locationAt("e1r1p1", "averylonglogin"),
+				locationAt("e1r1p2", "éàèùçôîêâ"),
+			},
+			ascii: true,
+			expected: "e1  2/3\n" +
+				"-------------------------------\n" +
+				"    p1       p2       p3\n" +
+				"r1  averylon éàèùçôîê .\n",
+		},
+		{
+			name:     "elsewhere",
+			clusters: []clusterLayout{{Name: "e1", Rows: 1, Posts: 1}},
+			locations: []*ftapi.Location{
+				locationAt("e1r1p1", "spoody"),
+				locationAt("e1r1p2", "mbounya"),
+				locationAt("e3r1p1", "norminet"),
+				locationAt("staff-mac", "bocal"),
+				{Host: "e1r0p1"},
+			},
+			ascii: true,
+			expected: "e1  1/1\n" +
+				"-------------\n" +
+				"    p1\n" +
+				"r1  spoody\n" +
+				"\n" +
+				"Elsewhere:  (e1r0p1), bocal (staff-mac), mbounya (e1r1p2), norminet (e3r1p1)\n",
+		},
+		{
+			name:      "only elsewhere",
+			locations: []*ftapi.Location{locationAt("staff-mac", "bocal")},
+			expected:  "Elsewhere: bocal (staff-mac)\n",
+		},
+	}
+	for _, test := range tests {
+		var out strings.Builder
+		renderClusterMap(&out, test.clusters, test.locations, test.ascii)
+		assert.Equal(t, test.expected, out.String(), test.name)
+	}
+}
+
+type locationsMapMockAPI struct {
+	baseMockAPI
+	t      *testing.T
+	client ftapi.APIInterface
+}
+
+func (m *locationsMapMockAPI) ListCampusLocationsContext(ctx context.Context, campusID int, params url.Values) *ftapi.Pager {
+	assert.Equal(m.t, "true", params.Get("filter[active]"))
+	return m.client.ListCampusLocationsContext(ctx, campusID, params)
+}
+
+func TestDrawLocationsMap(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/campus/21/locations":
+			_, _ = rw.Write([]byte(`[
+				{"id":2,"host":"e1r2p3","begin_at":"2021-11-02T08:00:00Z","user":{"login":"spoody"}},
+				{"id":3,"host":"e1r1p1","begin_at":"2021-11-02T09:00:00Z","user":{"login":"norminet"}}
+			]`))
+		case "/campus/16/locations":
+			_, _ = rw.Write([]byte(`[]`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	api := &locationsMapMockAPI{t: t, client: ftapi.New(server.URL, server.Client(), ftapi.WithRateLimiter(nil))}
+	inferred := "e1  2/6\n" +
+		"-------------------------------\n" +
+		"    p1       p2       p3\n" +
+		"r1  norminet .        .\n" +
+		"r2  .        .        spoody\n"
+
+	tests := []struct {
+		name     string
+		campusID int
+		layout   *mapLayout
+		expected string
+	}{
+		{
+			name:     "layout of the campus",
+			campusID: 21,
+			layout: &mapLayout{Campuses: map[int][]clusterLayout{
+				21: {{Name: "e1", Rows: 2, Posts: 4, RowPosts: map[int]int{1: 1}}},
+			}},
+			expected: "e1  2/5\n" +
+				"----------------------------------------\n" +
+				"    p1       p2       p3       p4\n" +
+				"r1  norminet\n" +
+				"r2  .        .        spoody   .\n",
+		},
+		{
+			name:     "no layout",
+			campusID: 21,
+			expected: inferred,
+		},
+		{
+			name:     "layout of another campus",
+			campusID: 21,
+			layout: &mapLayout{Campuses: map[int][]clusterLayout{
+				16: {{Name: "e1", Rows: 1, Posts: 1}},
+			}},
+			expected: inferred,
+		},
+		{
+			name:     "nobody logged in",
+			campusID: 16,
+			expected: "",
+		},
+	}
+	for _, test := range tests {
+		out := bytes.NewBufferString("")
+		assert.Nil(t, drawLocationsMap(context.Background(), out, api, test.campusID, test.layout, true), test.name)
+		assert.Equal(t, test.expected, out.String(), test.name)
+	}
+
+	err := drawLocationsMap(context.Background(), bytes.NewBufferString(""), api, 42, nil, true)
+	assert.NotNil(t, err)
+}
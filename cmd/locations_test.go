@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"goft/pkg/ftapi"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newLocationsServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query()
+		switch {
+		case req.URL.Path == "/campus/21/locations" && query.Get("filter[host]") == "e1r2p3" && query.Get("filter[active]") == "true":
+			_, _ = rw.Write([]byte(`[{"id":2,"host":"e1r2p3","begin_at":"2021-11-02T08:00:00Z","user":{"login":"spoody"}}]`))
+		case req.URL.Path == "/campus/21/locations" && query.Get("filter[host]") != "":
+			_, _ = rw.Write([]byte(`[]`))
+		case req.URL.Path == "/campus/21/locations":
+			assert.Equal(t, "true", query.Get("filter[active]"))
+			_, _ = rw.Write([]byte(`[
+				{"id":2,"host":"e1r2p3","begin_at":"2021-11-02T08:00:00Z","user":{"login":"spoody"}},
+				{"id":3,"host":"e1r1p1","begin_at":"2021-11-02T09:00:00Z","user":{"login":"norminet"}}
+			]`))
+		case req.URL.Path == "/users/spoody/locations":
+			assert.Equal(t, "-begin_at", query.Get("sort"))
+			assert.NotEmpty(t, query.Get("range[begin_at]"))
+			_, _ = rw.Write([]byte(`[
+				{"id":2,"host":"e1r2p3","begin_at":"2021-11-02T08:00:00Z","end_at":"2021-11-02T11:05:00Z"},
+				{"id":1,"host":"e2r5p8","begin_at":"2021-11-01T08:00:00Z","end_at":"2021-11-01T09:30:00Z"}
+			]`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFormatHours(t *testing.T) {
+	assert.Equal(t, "3h05", formatHours(3*time.Hour+5*time.Minute+20*time.Second))
+	assert.Equal(t, "0h00", formatHours(0))
+	assert.Equal(t, "26h30", formatHours(26*time.Hour+30*time.Minute))
+}
+
+func TestLocationsActive(t *testing.T) {
+	server := newLocationsServer(t)
+	var api ftapi.APIInterface = ftapi.New(server.URL, server.Client(), ftapi.WithRateLimiter(nil))
+	stdout := bytes.NewBufferString("")
+	activeCmd := NewLocationsActiveCmd(&api)
+	activeCmd.Flags().StringP("output", "o", "", outputFlagUsage)
+	activeCmd.SetArgs([]string{"--campus", "21", "-o", "template={{range .}}{{.host}} {{.user.login}}\n{{end}}"})
+	activeCmd.SetOut(stdout)
+	assert.Nil(t, activeCmd.Execute())
+	assert.Equal(t, "e1r1p1 norminet\ne1r2p3 spoody\n", stdout.String())
+}
+
+func TestLocationsUser(t *testing.T) {
+	server := newLocationsServer(t)
+	var api ftapi.APIInterface = ftapi.New(server.URL, server.Client(), ftapi.WithRateLimiter(nil))
+	stdout := bytes.NewBufferString("")
+	userCmd := NewLocationsUserCmd(&api)
+	userCmd.Flags().StringP("output", "o", "", outputFlagUsage)
+	userCmd.SetArgs([]string{"spoody", "--since", "7d", "-o", "csv"})
+	userCmd.SetOut(stdout)
+	assert.Nil(t, userCmd.Execute())
+	assert.Contains(t, stdout.String(), "LOGIN,HOST,BEGIN,END,DURATION\n,e1r2p3,")
+	assert.Contains(t, stdout.String(), ",3h05\n,e2r5p8,")
+}
+
+func TestLocationsHost(t *testing.T) {
+	server := newLocationsServer(t)
+	var api ftapi.APIInterface = ftapi.New(server.URL, server.Client(), ftapi.WithRateLimiter(nil))
+	stdout := bytes.NewBufferString("")
+	hostCmd := NewLocationsHostCmd(&api)
+	hostCmd.SetArgs([]string{"e1r2p3", "--campus", "21"})
+	hostCmd.SetOut(stdout)
+	assert.Nil(t, hostCmd.Execute())
+	assert.Regexp(t, `^spoody\te1r2p3\t2021-11-02 \d\d:00 - now \(\d+h\d\d\)\n$`, stdout.String())
+
+	stdout.Reset()
+	hostCmd = NewLocationsHostCmd(&api)
+	hostCmd.SetArgs([]string{"e3r1p1", "--campus", "21"})
+	hostCmd.SetOut(stdout)
+	assert.Nil(t, hostCmd.Execute())
+	assert.Equal(t, "Nobody is logged in on e3r1p1\n", stdout.String())
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"goft/pkg/ftapi"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// NewLocationsUserCmd creates the locations user cmd
+func NewLocationsUserCmd(api *ftapi.APIInterface) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "user login",
+		Short: "List the sessions of a user, the last first",
+		Long: `List the sessions of a user on the campus computers, the last first.
+
+Without --since only the current session is printed:
+  goft locations user norminet
+  goft locations user norminet --since 7d`,
+		Annotations: map[string]string{
+			argsAnnotation:  "login",
+			tokenAnnotation: "user",
+		},
+		ValidArgsFunction: completeArgs(api),
+		Args:              cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			login, err := resolveLogin(cmd.Context(), *api, args[0])
+			if err != nil {
+				return err
+			}
+			params, err := sinceParams(cmd, time.Now())
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("since") {
+				params.Set("filter[active]", "true")
+			}
+			var locations []*ftapi.Location
+			err = (*api).ListUserLocationsContext(cmd.Context(), login, params).PageSize(ftapi.MaxPageSize).All(&locations)
+			if err != nil {
+				return err
+			}
+			if len(locations) == 0 && outputFormat(cmd) == "" {
+				if since, _ := cmd.Flags().GetString("since"); since != "" {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s didn't log in since %s\n", login, since)
+					return nil
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s is not logged in\n", login)
+				return nil
+			}
+			return printLocations(cmd, true, locations)
+		},
+	}
+	cmd.Flags().String("since", "", "List the sessions begun since then instead of the current one, like 24h, 7d or 2006-01-02")
+	return cmd
+}
+
+var locationsUserCmd = NewLocationsUserCmd(&API)
+
+func init() {
+	locationsCmd.AddCommand(locationsUserCmd)
+}
@@ -0,0 +1,364 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goft/pkg/ftapi"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// logtimeDefaultDays is the number of days reported when --from isn't set
+const logtimeDefaultDays = 30
+
+// logtimePeriodKey returns the period of t: the day like 2021-11-02, the ISO week like 2021-W44 or the month like 2021-11
+func logtimePeriodKey(t time.Time, by string) string {
+	switch by {
+	case "week":
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case "month":
+		return t.Format("2006-01")
+	}
+	return t.Format("2006-01-02")
+}
+
+// logtimePeriods returns the periods between from and to in order, from and to are in the time zone of the report
+func logtimePeriods(from time.Time, to time.Time, by string) []string {
+	var periods []string
+	for day := from; day.Before(to); day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, day.Location()) {
+		if period := logtimePeriodKey(day, by); len(periods) == 0 || periods[len(periods)-1] != period {
+			periods = append(periods, period)
+		}
+	}
+	return periods
+}
+
+// computeLogtime sums the time logged in each period between from and to, the days are cut at midnight in the time zone of from.
+// Sessions still active end now, and overlapping sessions are only counted once
+func computeLogtime(locations []*ftapi.Location, from time.Time, to time.Time, now time.Time, by string) map[string]time.Duration {
+	type interval struct{ begin, end time.Time }
+	var intervals []interval
+	for _, location := range locations {
+		begin, end := location.BeginAt, now
+		if location.EndAt != nil {
+			end = *location.EndAt
+		}
+		if begin.Before(from) {
+			begin = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(begin) {
+			intervals = append(intervals, interval{begin, end})
+		}
+	}
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].begin.Before(intervals[j].begin) })
+	var merged []interval
+	for _, current := range intervals {
+		if last := len(merged) - 1; last >= 0 && !current.begin.After(merged[last].end) {
+			if current.end.After(merged[last].end) {
+				merged[last].end = current.end
+			}
+			continue
+		}
+		merged = append(merged, current)
+	}
+
+	loc := from.Location()
+	logtime := map[string]time.Duration{}
+	for _, current := range merged {
+		for begin := current.begin.In(loc); begin.Before(current.end); {
+			end := time.Date(begin.Year(), begin.Month(), begin.Day()+1, 0, 0, 0, 0, loc)
+			if end.After(current.end) {
+				end = current.end
+			}
+			logtime[logtimePeriodKey(begin, by)] += end.Sub(begin)
+			begin = end
+		}
+	}
+	return logtime
+}
+
+// userLogtime fetches the sessions of login overlapping from and to and sums them by period.
+// The closed sessions are looked up by their end, so a session begun long before from, like one left open
+// over the weekend, is still counted. The active sessions have no end yet and are fetched separately
+func userLogtime(ctx context.Context, api ftapi.APIInterface, login string, from time.Time, to time.Time, now time.Time, by string) (map[string]time.Duration, error) {
+	until := now
+	if to.After(until) {
+		until = to
+	}
+	params := url.Values{}
+	params.Set("range[end_at]", from.UTC().Format(time.RFC3339)+","+until.UTC().Format(time.RFC3339))
+	var locations []*ftapi.Location
+	err := api.ListUserLocationsContext(ctx, login, params).PageSize(ftapi.MaxPageSize).All(&locations)
+	if err != nil {
+		return nil, err
+	}
+	var active []*ftapi.Location
+	err = api.ListUserLocationsContext(ctx, login, url.Values{"filter[active]": {"true"}}).PageSize(ftapi.MaxPageSize).All(&active)
+	if err != nil {
+		return nil, err
+	}
+	return computeLogtime(append(locations, active...), from, to, now, by), nil
+}
+
+// hours formats a duration in decimal hours for the table and csv outputs, like 3.25
+func hours(d time.Duration) string {
+	return strconv.FormatFloat(d.Hours(), 'f', 2, 64)
+}
+
+// logtimeRow is the logtime of a user in a period
+type logtimeRow struct {
+	Period string  `json:"period"`
+	Hours  float64 `json:"hours"`
+}
+
+// cohortLogtimeRow is the logtime of a user of a cohort in every period
+type cohortLogtimeRow struct {
+	Login   string             `json:"login"`
+	Periods map[string]float64 `json:"periods"`
+	Total   float64            `json:"total"`
+}
+
+// parseCohort parses a cohort like 2021/july into its pool year and month
+func parseCohort(cohort string) (string, string, error) {
+	parts := strings.Split(cohort, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid cohort '%s', must be pool_year/pool_month like 2021/july", cohort)
+	}
+	if _, err := strconv.Atoi(parts[0]); err != nil {
+		return "", "", fmt.Errorf("invalid cohort '%s', must be pool_year/pool_month like 2021/july", cohort)
+	}
+	return parts[0], strings.ToLower(parts[1]), nil
+}
+
+// logtimeCampus returns the campus whose time zone is used: the one of --campus, else the primary campus of login,
+// else the campus_id of the config file, nil when none is known
+func logtimeCampus(cmd *cobra.Command, api ftapi.APIInterface, login string) (*ftapi.Campus, error) {
+	if campus, _ := cmd.Flags().GetString("campus"); campus != "" {
+		return resolveCampus(cmd.Context(), api, campus)
+	}
+	if login != "" {
+		user, err := api.GetUserByLoginContext(cmd.Context(), login)
+		if err != nil {
+			return nil, err
+		}
+		if campus := user.GetPrimaryCampus(); campus != nil {
+			return campus, nil
+		}
+	}
+	if campusID := viper.GetInt("campus_id"); campusID > 0 {
+		return api.GetCampusContext(cmd.Context(), campusID)
+	}
+	return nil, nil
+}
+
+// logtimeRange returns the --from and --to flags in loc, a --to date without time includes that day
+func logtimeRange(cmd *cobra.Command, loc *time.Location, now time.Time) (time.Time, time.Time, error) {
+	to := now.In(loc)
+	if value, _ := cmd.Flags().GetString("to"); value != "" {
+		var err error
+		to, err = parseDateIn(value, loc)
+		if err != nil {
+			return to, to, err
+		}
+		if len(value) == len("2006-01-02") {
+			to = to.AddDate(0, 0, 1)
+		}
+	}
+	from := time.Date(to.Year(), to.Month(), to.Day()-logtimeDefaultDays, 0, 0, 0, 0, loc)
+	if value, _ := cmd.Flags().GetString("from"); value != "" {
+		var err error
+		from, err = parseDateIn(value, loc)
+		if err != nil {
+			return from, to, err
+		}
+	}
+	if !from.Before(to) {
+		return from, to, errors.New("--from must be before --to")
+	}
+	return from, to, nil
+}
+
+// printUserLogtime prints the logtime of a user by period, with the total in the text output
+func printUserLogtime(cmd *cobra.Command, periods []string, logtime map[string]time.Duration) error {
+	var total time.Duration
+	rows := make([]logtimeRow, 0, len(periods))
+	t := table{Header: []string{"PERIOD", "HOURS"}}
+	for _, period := range periods {
+		total += logtime[period]
+		rows = append(rows, logtimeRow{Period: period, Hours: logtime[period].Hours()})
+		t.Rows = append(t.Rows, []string{period, hours(logtime[period])})
+	}
+	return printOutput(cmd, output{
+		Data:  rows,
+		Table: t,
+		Text: func(w io.Writer) {
+			for _, period := range periods {
+				_, _ = fmt.Fprintf(w, "%s\t%s\n", period, formatHours(logtime[period]))
+			}
+			_, _ = fmt.Fprintf(w, "Total\t%s\n", formatHours(total))
+		},
+	})
+}
+
+// printCohortLogtime prints a table of the logtime of every user of a cohort by period
+func printCohortLogtime(cmd *cobra.Command, periods []string, logins []string, logtimes []map[string]time.Duration) error {
+	rows := make([]cohortLogtimeRow, 0, len(logins))
+	t := table{Header: append(append([]string{"LOGIN"}, periods...), "TOTAL")}
+	totals := make([]time.Duration, len(logins))
+	for i, login := range logins {
+		row := cohortLogtimeRow{Login: login, Periods: map[string]float64{}}
+		cells := []string{login}
+		for _, period := range periods {
+			totals[i] += logtimes[i][period]
+			row.Periods[period] = logtimes[i][period].Hours()
+			cells = append(cells, hours(logtimes[i][period]))
+		}
+		row.Total = totals[i].Hours()
+		rows = append(rows, row)
+		t.Rows = append(t.Rows, append(cells, hours(totals[i])))
+	}
+	return printOutput(cmd, output{
+		Data:  rows,
+		Table: t,
+		Text: func(w io.Writer) {
+			tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', tabwriter.AlignRight)
+			_, _ = fmt.Fprintln(tw, strings.Join(t.Header, "\t")+"\t")
+			for i, login := range logins {
+				cells := []string{login}
+				for _, period := range periods {
+					cells = append(cells, formatHours(logtimes[i][period]))
+				}
+				_, _ = fmt.Fprintln(tw, strings.Join(append(cells, formatHours(totals[i])), "\t")+"\t")
+			}
+			_ = tw.Flush()
+		},
+	})
+}
+
+// NewLogtimeCmd creates the logtime cmd
+func NewLogtimeCmd(api *ftapi.APIInterface) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logtime [login]",
+		Short: "Report the time logged in on the campus computers",
+		Long: `Report the time a user was logged in on the campus computers, by day, week or month.
+
+The days are cut at midnight in the time zone of the campus given with --campus,
+else the primary campus of the user, else the campus_id of the config file.
+--from defaults to 30 days before --to, which defaults to now. A --to date without time includes that day:
+  goft logtime norminet --from 2021-11-01 --to 2021-11-30 --by week
+
+With --cohort, the logtime of every user of a piscine of the campus is reported in a table:
+  goft logtime --cohort 2021/july --campus benguerir --by week -o csv > piscine.csv`,
+		Annotations: map[string]string{
+			argsAnnotation: "login",
+		},
+		ValidArgsFunction: completeArgs(api),
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.MaximumNArgs(1)(cmd, args); err != nil {
+				return err
+			}
+			by, _ := cmd.Flags().GetString("by")
+			if by != "day" && by != "week" && by != "month" {
+				return errors.New("--by must be day, week or month")
+			}
+			cohort, _ := cmd.Flags().GetString("cohort")
+			if cohort == "" {
+				if len(args) == 0 {
+					return errors.New("login is required without --cohort")
+				}
+				return nil
+			}
+			if len(args) == 1 {
+				return errors.New("login can't be used with --cohort")
+			}
+			if _, _, err := parseCohort(cohort); err != nil {
+				return err
+			}
+			return checkCampusFlag(cmd)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			by, _ := cmd.Flags().GetString("by")
+			cohort, _ := cmd.Flags().GetString("cohort")
+			login := ""
+			if len(args) == 1 {
+				var err error
+				login, err = resolveLogin(cmd.Context(), *api, args[0])
+				if err != nil {
+					return err
+				}
+			}
+			campus, err := logtimeCampus(cmd, *api, login)
+			if err != nil {
+				return err
+			}
+			loc := time.Local
+			if campus != nil {
+				loc, err = campus.Location()
+				if err != nil {
+					return fmt.Errorf("time zone of %s: %w", campus.Name, err)
+				}
+			}
+			now := time.Now()
+			from, to, err := logtimeRange(cmd, loc, now)
+			if err != nil {
+				return err
+			}
+			periods := logtimePeriods(from, to, by)
+
+			if cohort == "" {
+				logtime, err := userLogtime(cmd.Context(), *api, login, from, to, now, by)
+				if err != nil {
+					return err
+				}
+				return printUserLogtime(cmd, periods, logtime)
+			}
+			if campus == nil {
+				return errors.New("--campus is required when campus_id is not set in the config file")
+			}
+			poolYear, poolMonth, _ := parseCohort(cohort)
+			params := url.Values{"filter[pool_year]": {poolYear}, "filter[pool_month]": {poolMonth}}
+			var users []*ftapi.User
+			err = (*api).ListCampusUsersContext(cmd.Context(), campus.ID, params).PageSize(ftapi.MaxPageSize).All(&users)
+			if err != nil {
+				return err
+			}
+			sort.Slice(users, func(i, j int) bool { return users[i].Login < users[j].Login })
+			logins := make([]string, 0, len(users))
+			logtimes := make([]map[string]time.Duration, 0, len(users))
+			for _, user := range users {
+				logtime, err := userLogtime(cmd.Context(), *api, user.Login, from, to, now, by)
+				if err != nil {
+					return err
+				}
+				logins = append(logins, user.Login)
+				logtimes = append(logtimes, logtime)
+			}
+			return printCohortLogtime(cmd, periods, logins, logtimes)
+		},
+	}
+	addCampusFlag(cmd, api)
+	cmd.Flags().String("from", "", "Beginning of the report, like 2006-01-02, defaults to 30 days before --to")
+	cmd.Flags().String("to", "", "End of the report, like 2006-01-02, defaults to now")
+	cmd.Flags().String("by", "day", "Sum the time by day, week or month")
+	cmd.Flags().String("cohort", "", "Report the users of a piscine of the campus instead of a login, like 2021/july")
+	return cmd
+}
+
+var logtimeCmd = NewLogtimeCmd(&API)
+
+func init() {
+	rootCmd.AddCommand(logtimeCmd)
+}
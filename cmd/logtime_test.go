@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"goft/pkg/ftapi"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeLogtime(t *testing.T) {
+	// UTC+1, the session from 22:30 to 01:00 UTC is cut at 23:00 UTC
+	loc := time.FixedZone("UTC+1", 3600)
+	at := func(day, hour, minute int) *time.Time {
+		date := time.Date(2021, 11, day, hour, minute, 0, 0, time.UTC)
+		return &date
+	}
+	locations := []*ftapi.Location{
+		{BeginAt: *at(1, 22, 30), EndAt: at(2, 1, 0)},
+		// Overlaps the previous session on another host
+		{BeginAt: *at(2, 0, 0), EndAt: at(2, 2, 0)},
+		{BeginAt: *at(8, 9, 0), EndAt: at(8, 12, 15)},
+		// Still active, it ends now
+		{BeginAt: *at(15, 10, 0)},
+		// Before the report
+		{BeginAt: *at(1, 8, 0), EndAt: at(1, 9, 0)},
+	}
+	from := time.Date(2021, 11, 1, 12, 0, 0, 0, loc)
+	to := time.Date(2021, 11, 16, 0, 0, 0, 0, loc)
+	now := *at(15, 11, 0)
+
+	logtime := computeLogtime(locations, from, to, now, "day")
+	assert.Equal(t, map[string]time.Duration{
+		"2021-11-01": 30 * time.Minute,
+		"2021-11-02": 3 * time.Hour,
+		"2021-11-08": 3*time.Hour + 15*time.Minute,
+		"2021-11-15": time.Hour,
+	}, logtime)
+
+	logtime = computeLogtime(locations, from, to, now, "week")
+	assert.Equal(t, map[string]time.Duration{
+		"2021-W44": 3*time.Hour + 30*time.Minute,
+		"2021-W45": 3*time.Hour + 15*time.Minute,
+		"2021-W46": time.Hour,
+	}, logtime)
+
+	logtime = computeLogtime(locations, from, to, now, "month")
+	assert.Equal(t, map[string]time.Duration{"2021-11": 7*time.Hour + 45*time.Minute}, logtime)
+}
+
+func TestLogtimePeriods(t *testing.T) {
+	from := time.Date(2021, 10, 30, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 11, 2, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, []string{"2021-10-30", "2021-10-31", "2021-11-01"}, logtimePeriods(from, to, "day"))
+	assert.Equal(t, []string{"2021-W43", "2021-W44"}, logtimePeriods(from, to, "week"))
+	assert.Equal(t, []string{"2021-10", "2021-11"}, logtimePeriods(from, to, "month"))
+}
+
+func TestParseCohort(t *testing.T) {
+	year, month, err := parseCohort("2021/July")
+	assert.Nil(t, err)
+	assert.Equal(t, "2021", year)
+	assert.Equal(t, "july", month)
+	_, _, err = parseCohort("july")
+	assert.EqualError(t, err, "invalid cohort 'july', must be pool_year/pool_month like 2021/july")
+}
+
+func newLogtimeServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		// Nobody is logged in, the closed sessions below are returned for the range[end_at] queries
+		if req.URL.Query().Get("filter[active]") == "true" {
+			_, _ = rw.Write([]byte(`[]`))
+			return
+		}
+		switch req.URL.Path {
+		case "/campus/21":
+			_, _ = rw.Write([]byte(`{"id":21,"name":"Benguerir"}`))
+		case "/campus/21/users":
+			assert.Equal(t, "2021", req.URL.Query().Get("filter[pool_year]"))
+			assert.Equal(t, "july", req.URL.Query().Get("filter[pool_month]"))
+			_, _ = rw.Write([]byte(`[{"login":"spoody"},{"login":"norminet"}]`))
+		case "/users/spoody/locations":
+			_, _ = rw.Write([]byte(`[{"host":"e1r2p3","begin_at":"2021-07-05T08:00:00Z","end_at":"2021-07-05T10:30:00Z"}]`))
+		case "/users/norminet/locations":
+			_, _ = rw.Write([]byte(`[{"host":"e1r1p1","begin_at":"2021-07-12T08:00:00Z","end_at":"2021-07-12T09:00:00Z"}]`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLogtimeUser(t *testing.T) {
+	server := newLogtimeServer(t)
+	var api ftapi.APIInterface = ftapi.New(server.URL, server.Client(), ftapi.WithRateLimiter(nil))
+	stdout := bytes.NewBufferString("")
+	logtimeCmd := NewLogtimeCmd(&api)
+	logtimeCmd.SetArgs([]string{"spoody", "--campus", "21", "--from", "2021-07-01", "--to", "2021-07-31", "--by", "week"})
+	logtimeCmd.SetOut(stdout)
+	assert.Nil(t, logtimeCmd.Execute())
+	assert.Equal(t, "2021-W26\t0h00\n2021-W27\t2h30\n2021-W28\t0h00\n2021-W29\t0h00\n2021-W30\t0h00\nTotal\t2h30\n", stdout.String())
+}
+
+func TestLogtimeCohort(t *testing.T) {
+	server := newLogtimeServer(t)
+	var api ftapi.APIInterface = ftapi.New(server.URL, server.Client(), ftapi.WithRateLimiter(nil))
+	stdout := bytes.NewBufferString("")
+	logtimeCmd := NewLogtimeCmd(&api)
+	logtimeCmd.Flags().StringP("output", "o", "", outputFlagUsage)
+	logtimeCmd.SetArgs([]string{"--cohort", "2021/july", "--campus", "21", "--from", "2021-07-01", "--to", "2021-07-31", "--by", "month", "-o", "csv"})
+	logtimeCmd.SetOut(stdout)
+	assert.Nil(t, logtimeCmd.Execute())
+	assert.Equal(t, "LOGIN,2021-07,TOTAL\nnorminet,1.00,1.00\nspoody,2.50,2.50\n", stdout.String())
+}
+
+func TestUserLogtimeLongSessions(t *testing.T) {
+	sessions := []struct {
+		begin, end string
+	}{
+		// Left open over the weekend, begun two days before from
+		{"2021-06-28T18:00:00Z", "2021-07-01T10:00:00Z"},
+		{"2021-07-02T08:00:00Z", "2021-07-02T09:00:00Z"},
+		// Active
+		{"2021-07-02T20:00:00Z", ""},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/users/spoody/locations", req.URL.Path)
+		query := req.URL.Query()
+		assert.Empty(t, query.Get("range[begin_at]"))
+		var bounds []string
+		if query.Get("range[end_at]") != "" {
+			bounds = strings.Split(query.Get("range[end_at]"), ",")
+		}
+		var found []string
+		for _, session := range sessions {
+			switch {
+			case query.Get("filter[active]") == "true" && session.end == "":
+				found = append(found, fmt.Sprintf(`{"host":"e1r2p3","begin_at":"%s"}`, session.begin))
+			case len(bounds) == 2 && session.end != "" && session.end >= bounds[0] && session.end <= bounds[1]:
+				found = append(found, fmt.Sprintf(`{"host":"e1r2p3","begin_at":"%s","end_at":"%s"}`, session.begin, session.end))
+			}
+		}
+		_, _ = rw.Write([]byte("[" + strings.Join(found, ",") + "]"))
+	}))
+	t.Cleanup(server.Close)
+	var api ftapi.APIInterface = ftapi.New(server.URL, server.Client(), ftapi.WithRateLimiter(nil))
+
+	from := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2021, 7, 3, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2021, 7, 2, 22, 0, 0, 0, time.UTC)
+	logtime, err := userLogtime(context.Background(), api, "spoody", from, to, now, "day")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]time.Duration{"2021-07-01": 10 * time.Hour, "2021-07-02": 3 * time.Hour}, logtime)
+}
@@ -0,0 +1,223 @@
+package cmd
+
+import (
+	"context"
+	"goft/pkg/ftapi"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// baseMockAPI implements ftapi.APIInterface with no-op methods,
+// tests embed it in their own mock and override what they need
+type baseMockAPI struct{}
+
+func (m *baseMockAPI) Get(url string) (*http.Response, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) GetContext(ctx context.Context, url string) (*http.Response, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) Delete(url string, contentType string, body io.Reader) (*http.Response, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) DeleteContext(ctx context.Context, url string, contentType string, body io.Reader) (*http.Response, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) Post(url string, contentType string, body io.Reader) (*http.Response, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) PostContext(ctx context.Context, url string, contentType string, body io.Reader) (*http.Response, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) PostJSON(url string, data interface{}) (*http.Response, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) PostJSONContext(ctx context.Context, url string, data interface{}) (*http.Response, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) Patch(url string, contentType string, body io.Reader) (*http.Response, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) PatchContext(ctx context.Context, url string, contentType string, body io.Reader) (*http.Response, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) PatchJSON(url string, data interface{}) (*http.Response, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) PatchJSONContext(ctx context.Context, url string, data interface{}) (*http.Response, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) CreateUser(user *ftapi.User, campusID int) error {
+	return nil
+}
+func (m *baseMockAPI) CreateUserContext(ctx context.Context, user *ftapi.User, campusID int) error {
+	return nil
+}
+func (m *baseMockAPI) SetUserImage(login string, img *os.File) error {
+	return nil
+}
+func (m *baseMockAPI) SetUserImageContext(ctx context.Context, login string, img *os.File) error {
+	return nil
+}
+func (m *baseMockAPI) CreateClose(close *ftapi.Close) error {
+	return nil
+}
+func (m *baseMockAPI) CreateCloseContext(ctx context.Context, close *ftapi.Close) error {
+	return nil
+}
+func (m *baseMockAPI) GetUserByLogin(login string) (*ftapi.User, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) GetUserByLoginContext(ctx context.Context, login string) (*ftapi.User, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) GetUserByEmail(email string) (*ftapi.User, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) GetUserByEmailContext(ctx context.Context, email string) (*ftapi.User, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) GetMe() (*ftapi.User, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) GetMeContext(ctx context.Context) (*ftapi.User, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) GetTokenInfo() (*ftapi.TokenInfo, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) GetTokenInfoContext(ctx context.Context) (*ftapi.TokenInfo, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) UpdateUser(login string, data *ftapi.User) error {
+	return nil
+}
+func (m *baseMockAPI) UpdateUserContext(ctx context.Context, login string, data *ftapi.User) error {
+	return nil
+}
+func (m *baseMockAPI) AddCorrectionPoints(login string, points uint, reason string) error {
+	return nil
+}
+func (m *baseMockAPI) AddCorrectionPointsContext(ctx context.Context, login string, points uint, reason string) error {
+	return nil
+}
+func (m *baseMockAPI) RemoveCorrectionPoints(login string, points uint, reason string) error {
+	return nil
+}
+func (m *baseMockAPI) RemoveCorrectionPointsContext(ctx context.Context, login string, points uint, reason string) error {
+	return nil
+}
+func (m *baseMockAPI) GetUserAgus(login string) ([]ftapi.Agu, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) GetUserAgusContext(ctx context.Context, login string) ([]ftapi.Agu, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) CreateFreePastAgu(login string, duration int, reason string) error {
+	return nil
+}
+func (m *baseMockAPI) CreateFreePastAguContext(ctx context.Context, login string, duration int, reason string) error {
+	return nil
+}
+func (m *baseMockAPI) GetProjectByName(name string) (*ftapi.Project, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) GetProjectByNameContext(ctx context.Context, name string) (*ftapi.Project, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) GetUserProjects(login string, filter_param map[string]string, range_param map[string]string, page_number int) ([]*ftapi.ProjectUser, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) GetUserProjectsContext(ctx context.Context, login string, filter_param map[string]string, range_param map[string]string, page_number int) ([]*ftapi.ProjectUser, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) ListUserProjects(login string, filter_param map[string]string, range_param map[string]string) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) ListUserProjectsContext(ctx context.Context, login string, filter_param map[string]string, range_param map[string]string) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) GetCampus(id int) (*ftapi.Campus, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) GetCampusContext(ctx context.Context, id int) (*ftapi.Campus, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) ListCampuses(params url.Values) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) ListCampusesContext(ctx context.Context, params url.Values) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) ListCampusUsers(campusID int, params url.Values) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) ListCampusUsersContext(ctx context.Context, campusID int, params url.Values) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) ListCursusUsers(cursusID int, params url.Values) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) ListCursusUsersContext(ctx context.Context, cursusID int, params url.Values) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) ListCampusCursusUsers(campusID int, params url.Values) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) ListCampusCursusUsersContext(ctx context.Context, campusID int, params url.Values) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) ListCampusLocations(campusID int, params url.Values) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) ListCampusLocationsContext(ctx context.Context, campusID int, params url.Values) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) ListUserLocations(login string, params url.Values) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) ListUserLocationsContext(ctx context.Context, login string, params url.Values) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) ListCursus(params url.Values) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) ListCursusContext(ctx context.Context, params url.Values) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) GetUserCursus(login string) ([]*ftapi.CursusUser, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) GetUserCursusContext(ctx context.Context, login string) ([]*ftapi.CursusUser, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) GetCursusUser(id int) (*ftapi.CursusUser, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) GetCursusUserContext(ctx context.Context, id int) (*ftapi.CursusUser, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) AddUserToCursus(userID int, cursusID int, beginAt time.Time, endAt *time.Time) (*ftapi.CursusUser, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) AddUserToCursusContext(ctx context.Context, userID int, cursusID int, beginAt time.Time, endAt *time.Time) (*ftapi.CursusUser, error) {
+	return nil, nil
+}
+func (m *baseMockAPI) UpdateCursusUser(id int, data *ftapi.CursusUser) error {
+	return nil
+}
+func (m *baseMockAPI) UpdateCursusUserContext(ctx context.Context, id int, data *ftapi.CursusUser) error {
+	return nil
+}
+func (m *baseMockAPI) Paginate(url string, params url.Values) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) PaginateContext(ctx context.Context, url string, params url.Values) *ftapi.Pager {
+	return nil
+}
+func (m *baseMockAPI) RateLimit() ftapi.RateLimit {
+	return ftapi.RateLimit{}
+}
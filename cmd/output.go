@@ -0,0 +1,245 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// outputFlagUsage documents the --output flag shared by read commands
+const outputFlagUsage = "Output format: json, jsonl, yaml, table, csv or template=<go template> (defaults to human readable text)"
+
+// table is the tabular view of a command's result, used by the table and csv output formats
+type table struct {
+	Header []string
+	Rows   [][]string
+}
+
+// output describes how a command's result is printed
+type output struct {
+	// Data is the result encoded by the json, yaml and template formats
+	Data interface{}
+	// Table is used by the table and csv formats
+	Table table
+	// Text prints the human readable output, used when --output is not set
+	Text func(w io.Writer)
+}
+
+// outputFormat returns the value of the --output flag, empty when the command doesn't have it
+func outputFormat(cmd *cobra.Command) string {
+	flag := cmd.Flag("output")
+	if flag == nil {
+		return ""
+	}
+	return flag.Value.String()
+}
+
+// validateOutputFormat returns an error if format is not a supported output format
+func validateOutputFormat(format string) error {
+	switch {
+	case format == "", format == "json", format == "jsonl", format == "yaml", format == "table", format == "csv":
+		return nil
+	case strings.HasPrefix(format, "template="):
+		_, err := template.New("output").Parse(strings.TrimPrefix(format, "template="))
+		return err
+	}
+	return fmt.Errorf("invalid output '%s', must be one of json, jsonl, yaml, table, csv or template=<go template>", format)
+}
+
+// printOutput prints out in the format selected with --output
+func printOutput(cmd *cobra.Command, out output) error {
+	w := cmd.OutOrStdout()
+	format := outputFormat(cmd)
+	switch {
+	case format == "":
+		out.Text(w)
+		return nil
+	case format == "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "\t")
+		return encoder.Encode(out.Data)
+	case format == "jsonl":
+		return writeJSONLines(w, out.Data)
+	case format == "yaml":
+		data, err := genericData(out.Data)
+		if err != nil {
+			return err
+		}
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(data); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case format == "table":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, strings.Join(out.Table.Header, "\t"))
+		for _, row := range out.Table.Rows {
+			_, _ = fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	case format == "csv":
+		cw := csv.NewWriter(w)
+		if err := cw.Write(out.Table.Header); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(out.Table.Rows); err != nil {
+			return err
+		}
+		return cw.Error()
+	case strings.HasPrefix(format, "template="):
+		tmpl, err := template.New("output").Parse(strings.TrimPrefix(format, "template="))
+		if err != nil {
+			return err
+		}
+		data, err := genericData(out.Data)
+		if err != nil {
+			return err
+		}
+		return tmpl.Execute(w, data)
+	}
+	return validateOutputFormat(format)
+}
+
+// writeJSONLines writes every element of data on its own line, or data itself when it isn't an array
+func writeJSONLines(w io.Writer, data interface{}) error {
+	generic, err := genericData(data)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	elements, ok := generic.([]interface{})
+	if !ok {
+		return encoder.Encode(generic)
+	}
+	for _, element := range elements {
+		if err := encoder.Encode(element); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rowWriter prints the items of long lists as they are fetched instead of once they all are,
+// in the formats that print one item per line: csv, jsonl and text
+type rowWriter struct {
+	format string
+	w      io.Writer
+	header []string
+	csv    *csv.Writer
+	json   *json.Encoder
+}
+
+// isStreamedFormat returns true for the formats supported by rowWriter
+func isStreamedFormat(format string) bool {
+	return format == "" || format == "csv" || format == "jsonl"
+}
+
+// newRowWriter creates a rowWriter printing to the output of cmd, header is the first csv line
+func newRowWriter(cmd *cobra.Command, header []string) *rowWriter {
+	w := cmd.OutOrStdout()
+	return &rowWriter{format: outputFormat(cmd), w: w, header: header, csv: csv.NewWriter(w), json: json.NewEncoder(w)}
+}
+
+// Write prints an item, data is its jsonl form, row its csv row and text its human readable line
+func (r *rowWriter) Write(data interface{}, row []string, text string) error {
+	switch r.format {
+	case "jsonl":
+		return r.json.Encode(data)
+	case "csv":
+		if r.header != nil {
+			if err := r.csv.Write(r.header); err != nil {
+				return err
+			}
+			r.header = nil
+		}
+		if err := r.csv.Write(row); err != nil {
+			return err
+		}
+		// Flushed on every row so the rows are seen as soon as they are fetched
+		r.csv.Flush()
+		return r.csv.Error()
+	}
+	_, err := fmt.Fprintln(r.w, text)
+	return err
+}
+
+// genericData converts data to maps and slices through its json encoding,
+// so yaml keys and template fields are the same as the json ones
+func genericData(data interface{}) (interface{}, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	err = json.Unmarshal(encoded, &generic)
+	return generic, err
+}
+
+// genericTable builds the table of a json value: one row per element for arrays of objects
+// and one row per key for objects, nested values are printed as json
+func genericTable(data interface{}) table {
+	switch value := data.(type) {
+	case []interface{}:
+		keys := map[string]bool{}
+		for _, element := range value {
+			if object, ok := element.(map[string]interface{}); ok {
+				for key := range object {
+					keys[key] = true
+				}
+			}
+		}
+		if len(keys) == 0 {
+			t := table{Header: []string{"VALUE"}}
+			for _, element := range value {
+				t.Rows = append(t.Rows, []string{cellText(element)})
+			}
+			return t
+		}
+		t := table{}
+		for key := range keys {
+			t.Header = append(t.Header, key)
+		}
+		sort.Strings(t.Header)
+		for _, element := range value {
+			object, _ := element.(map[string]interface{})
+			row := make([]string, len(t.Header))
+			for i, key := range t.Header {
+				row[i] = cellText(object[key])
+			}
+			t.Rows = append(t.Rows, row)
+		}
+		return t
+	case map[string]interface{}:
+		t := table{Header: []string{"KEY", "VALUE"}}
+		for key := range value {
+			t.Rows = append(t.Rows, []string{key, ""})
+		}
+		sort.Slice(t.Rows, func(i, j int) bool { return t.Rows[i][0] < t.Rows[j][0] })
+		for _, row := range t.Rows {
+			row[1] = cellText(value[row[0]])
+		}
+		return t
+	}
+	return table{Header: []string{"VALUE"}, Rows: [][]string{{cellText(data)}}}
+}
+
+// cellText formats a json value in a table cell
+func cellText(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	}
+	encoded, _ := json.Marshal(value)
+	return string(encoded)
+}
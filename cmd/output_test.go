@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+type outputTestItem struct {
+	Login  string `json:"login"`
+	Wallet int    `json:"wallet"`
+}
+
+func printTestOutput(t *testing.T, format string) string {
+	stdout := bytes.NewBufferString("")
+	cmd := &cobra.Command{}
+	cmd.Flags().StringP("output", "o", "", outputFlagUsage)
+	cmd.SetOut(stdout)
+	if format != "" {
+		assert.Nil(t, cmd.Flags().Set("output", format))
+	}
+	err := printOutput(cmd, output{
+		Data: []outputTestItem{{Login: "spoody", Wallet: 42}, {Login: "mbouzaie", Wallet: 7}},
+		Table: table{
+			Header: []string{"LOGIN", "WALLET"},
+			Rows:   [][]string{{"spoody", "42"}, {"mbouzaie", "7"}},
+		},
+		Text: func(w io.Writer) {
+			_, _ = io.WriteString(w, "human text\n")
+		},
+	})
+	assert.Nil(t, err)
+	return stdout.String()
+}
+
+func TestPrintOutput(t *testing.T) {
+	assert.Equal(t, "human text\n", printTestOutput(t, ""))
+	assert.Equal(t, `[
+	{
+		"login": "spoody",
+		"wallet": 42
+	},
+	{
+		"login": "mbouzaie",
+		"wallet": 7
+	}
+]
+`, printTestOutput(t, "json"))
+	assert.Equal(t, `- login: spoody
+  wallet: 42
+- login: mbouzaie
+  wallet: 7
+`, printTestOutput(t, "yaml"))
+	assert.Equal(t, `LOGIN     WALLET
+spoody    42
+mbouzaie  7
+`, printTestOutput(t, "table"))
+	assert.Equal(t, "LOGIN,WALLET\nspoody,42\nmbouzaie,7\n", printTestOutput(t, "csv"))
+	assert.Equal(t, "{\"login\":\"spoody\",\"wallet\":42}\n{\"login\":\"mbouzaie\",\"wallet\":7}\n", printTestOutput(t, "jsonl"))
+	assert.Equal(t, "spoody mbouzaie ", printTestOutput(t, "template={{range .}}{{.login}} {{end}}"))
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	assert.Nil(t, validateOutputFormat(""))
+	assert.Nil(t, validateOutputFormat("csv"))
+	assert.Nil(t, validateOutputFormat("template={{.login}}"))
+	assert.NotNil(t, validateOutputFormat("template={{.login"))
+	assert.EqualError(t, validateOutputFormat("xml"), "invalid output 'xml', must be one of json, jsonl, yaml, table, csv or template=<go template>")
+}
+
+func TestGenericTable(t *testing.T) {
+	data, err := genericData([]map[string]interface{}{
+		{"id": 1, "login": "spoody"},
+		{"id": 2, "campus": map[string]int{"id": 21}},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, table{
+		Header: []string{"campus", "id", "login"},
+		Rows:   [][]string{{"", "1", "spoody"}, {`{"id":21}`, "2", ""}},
+	}, genericTable(data))
+
+	data, err = genericData(map[string]interface{}{"login": "spoody", "id": 1})
+	assert.Nil(t, err)
+	assert.Equal(t, table{
+		Header: []string{"KEY", "VALUE"},
+		Rows:   [][]string{{"id", "1"}, {"login", "spoody"}},
+	}, genericTable(data))
+}
@@ -14,44 +14,55 @@ func NewCloneProjectCmd(api *ftapi.APIInterface) *cobra.Command {
 	return &cobra.Command{
 		Use:   "clone <project slug>",
 		Short: "Clone a repogitory locally",
-		Args:  cobra.RangeArgs(1, 2),
+		Annotations: map[string]string{
+			argsAnnotation:  "project",
+			tokenAnnotation: "user",
+		},
+		ValidArgsFunction: completeArgs(api),
+		Args:              cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			user, err := cmd.PersistentFlags().GetString("user")
 			if err != nil {
 				return err
 			}
-		loop:
-			for i := 1; ; i++ {
-				projects, err := (*api).GetUserProjects(user, nil, nil, i)
+			if user == "" {
+				user = defaultLogin()
+			}
+			user, err = resolveLogin(cmd.Context(), *api, user)
+			if err != nil {
+				return err
+			}
+			pager := (*api).ListUserProjectsContext(cmd.Context(), user, nil, nil)
+			for pager.Next() {
+				var project ftapi.ProjectUser
+				err = pager.Decode(&project)
 				if err != nil {
 					return err
 				}
-				if len(projects) == 0 {
+				if args[0] != project.Project.Slug {
+					continue
+				}
+				if len(project.Teams) == 0 {
 					break
 				}
-				for _, project := range projects {
-					if args[0] != project.Project.Slug {
-						continue
-					}
-					if len(project.Teams) == 0 {
-						break loop
-					}
-					var targetPath string
-					if len(args) == 2 {
-						targetPath = args[1]
-					} else {
-						targetPath = args[0]
-					}
-					team, err := currentTeam(project)
-					if err != nil {
-						return err
-					}
-					if team.RepoURL == "" {
-						fmt.Fprintf(os.Stderr, "repository not found: %s\n", args[0])
-						return nil
-					}
-					return cloneRepo(team.RepoURL, targetPath)
+				var targetPath string
+				if len(args) == 2 {
+					targetPath = args[1]
+				} else {
+					targetPath = args[0]
 				}
+				team, err := currentTeam(&project)
+				if err != nil {
+					return err
+				}
+				if team.RepoURL == "" {
+					fmt.Fprintf(os.Stderr, "repository not found: %s\n", args[0])
+					return nil
+				}
+				return cloneRepo(team.RepoURL, targetPath)
+			}
+			if err := pager.Err(); err != nil {
+				return err
 			}
 			return fmt.Errorf("%s's team is not locked.", args[0])
 		},
@@ -91,6 +102,7 @@ func cloneRepo(repoURL, targetPath string) error {
 var cloneProjectCmd = NewCloneProjectCmd(&API)
 
 func init() {
-	cloneProjectCmd.PersistentFlags().StringP("user", "u", os.Getenv("USER"), "Set specific user")
+	cloneProjectCmd.PersistentFlags().StringP("user", "u", "", "Set specific user (defaults to me when logged in, $USER otherwise)")
+	_ = cloneProjectCmd.RegisterFlagCompletionFunc("user", completeLogins)
 	projectsCmd.AddCommand(cloneProjectCmd)
 }
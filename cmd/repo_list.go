@@ -3,16 +3,25 @@ package cmd
 import (
 	"fmt"
 	"goft/pkg/ftapi"
-	"os"
+	"io"
 
 	"github.com/spf13/cobra"
 )
 
+// projectRepo is a project of the user and the repository of its current team
+type projectRepo struct {
+	Slug    string `json:"slug"`
+	RepoURL string `json:"repo_url"`
+}
+
 func NewGetProjectListCmd(api *ftapi.APIInterface) *cobra.Command {
 	return &cobra.Command{
 		Use:   "list",
 		Short: "Show team locked project list",
-		Args:  cobra.ExactArgs(0),
+		Annotations: map[string]string{
+			tokenAnnotation: "user",
+		},
+		Args: cobra.ExactArgs(0),
 		RunE: func(cmd *cobra.Command, args []string) error {
 
 			limit, err := cmd.PersistentFlags().GetInt("limit")
@@ -23,55 +32,66 @@ func NewGetProjectListCmd(api *ftapi.APIInterface) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			isQuiet, err := cmd.PersistentFlags().GetBool("quiet")
+			if user == "" {
+				user = defaultLogin()
+			}
+			user, err = resolveLogin(cmd.Context(), *api, user)
 			if err != nil {
 				return err
 			}
-			if !isQuiet {
-				fmt.Printf("\nShowing %d projects in @%s\n\n", limit, user)
+			isQuiet, err := cmd.PersistentFlags().GetBool("quiet")
+			if err != nil {
+				return err
 			}
-
+			var repos []projectRepo
 			count := 0
-		loop:
-			for i := 1; ; i++ {
-				projects, err := (*api).GetUserProjects(user, nil, nil, i)
+			pager := (*api).ListUserProjectsContext(cmd.Context(), user, nil, nil)
+			for count < limit && pager.Next() {
+				var project ftapi.ProjectUser
+				err = pager.Decode(&project)
 				if err != nil {
 					return err
 				}
-				if len(projects) == 0 {
-					break
+				if len(project.Teams) == 0 {
+					continue
 				}
+				count++
 
-				for _, project := range projects {
-					if len(project.Teams) == 0 {
-						continue
-					}
-					count++
-
-					team, err := currentTeam(project)
-					if err != nil {
-						return err
-					}
+				team, err := currentTeam(&project)
+				if err != nil {
+					return err
+				}
 
-					var repo string
-					if team.RepoURL == "" {
-						repo = "repository not found"
-					} else {
-						repo = team.RepoURL
-					}
+				repos = append(repos, projectRepo{Slug: project.Project.Slug, RepoURL: team.RepoURL})
+			}
+			if err := pager.Err(); err != nil {
+				return err
+			}
 
-					if isQuiet {
-						fmt.Println(project.Project.Slug)
-					} else {
-						fmt.Printf("%-25s %s\n", project.Project.Slug, repo)
+			t := table{Header: []string{"SLUG", "REPOSITORY"}}
+			for _, repo := range repos {
+				t.Rows = append(t.Rows, []string{repo.Slug, repo.RepoURL})
+			}
+			return printOutput(cmd, output{
+				Data:  repos,
+				Table: t,
+				Text: func(w io.Writer) {
+					if !isQuiet {
+						_, _ = fmt.Fprintf(w, "\nShowing %d projects in @%s\n\n", limit, user)
 					}
-
-					if count >= limit {
-						break loop
+					for _, repo := range repos {
+						if isQuiet {
+							_, _ = fmt.Fprintln(w, repo.Slug)
+							continue
+						}
+						url := repo.RepoURL
+						if url == "" {
+							url = "repository not found"
+						}
+						_, _ = fmt.Fprintf(w, "%-25s %s\n", repo.Slug, url)
 					}
-				}
-			}
-			return nil
+				},
+			})
 		},
 	}
 }
@@ -80,7 +100,8 @@ var getProjectListCmd = NewGetProjectListCmd(&API)
 
 func init() {
 	getProjectListCmd.PersistentFlags().IntP("limit", "L", 5, "Maximum number of repositories to list")
-	getProjectListCmd.PersistentFlags().StringP("user", "u", os.Getenv("USER"), "Set specific user")
+	getProjectListCmd.PersistentFlags().StringP("user", "u", "", "Set specific user (defaults to me when logged in, $USER otherwise)")
+	_ = getProjectListCmd.RegisterFlagCompletionFunc("user", completeLogins)
 	getProjectListCmd.PersistentFlags().BoolP("quiet", "q", false, "Only display project slug")
 	projectsCmd.AddCommand(getProjectListCmd)
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/spf13/cobra"
 	"goft/pkg/ftapi"
+	"io"
 	"io/ioutil"
 )
 
@@ -17,7 +18,7 @@ func NewRequestsGetCmd(api *ftapi.APIInterface) *cobra.Command {
 		Long: `Send a GET request to path, the path must be the part after /v2/`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			resp, err := (*api).Get(args[0])
+			resp, err := (*api).GetContext(cmd.Context(), args[0])
 			if err != nil {
 				return err
 			}
@@ -25,11 +26,26 @@ func NewRequestsGetCmd(api *ftapi.APIInterface) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			var prettyJSON bytes.Buffer
-			_ = json.Indent(&prettyJSON, bodyBytes, "", "\t")
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", resp.Status)
-			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\n", prettyJSON.String())
-			return nil
+			text := func(w io.Writer) {
+				var prettyJSON bytes.Buffer
+				_ = json.Indent(&prettyJSON, bodyBytes, "", "\t")
+				_, _ = fmt.Fprintf(w, "%s\n", resp.Status)
+				_, _ = fmt.Fprintf(w, "%s\n", prettyJSON.String())
+			}
+			if outputFormat(cmd) == "" {
+				return printOutput(cmd, output{Text: text})
+			}
+			// The other formats need the body to be json
+			var data interface{}
+			err = json.Unmarshal(bodyBytes, &data)
+			if err != nil {
+				return fmt.Errorf("%s: response is not json: %w", resp.Status, err)
+			}
+			return printOutput(cmd, output{
+				Data:  data,
+				Table: genericTable(data),
+				Text:  text,
+			})
 		},
 	}
 }
@@ -26,7 +26,7 @@ func NewRequestsPostCmd(api *ftapi.APIInterface) *cobra.Command {
 				payload += line+"\n"
 			}
 			fmt.Println(payload)
-			resp, err := (*api).Post(args[0], "application/json", bytes.NewReader([]byte(payload)))
+			resp, err := (*api).PostContext(cmd.Context(), args[0], "application/json", bytes.NewReader([]byte(payload)))
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"goft/pkg/ftapi"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// findHistoryEntry returns the entry with the given id
+func findHistoryEntry(entries []historyEntry, id int) (historyEntry, error) {
+	for _, entry := range entries {
+		if entry.ID == id {
+			return entry, nil
+		}
+	}
+	return historyEntry{}, fmt.Errorf("#%d not found in the history, see goft history", id)
+}
+
+// previousCursusUser builds the update restoring the fields changed by an update_cursus_user entry,
+// the fields which were empty can't be emptied again and fail it when nothing else can be restored
+func previousCursusUser(entry historyEntry) (ftapi.CursusUser, error) {
+	previous := ftapi.CursusUser{Grade: entry.Previous["grade"]}
+	restored := previous.Grade != ""
+	for field, date := range map[string]**time.Time{"end_at": &previous.EndAt, "blackholed_at": &previous.BlackholedAt} {
+		if entry.Previous[field] == "" {
+			continue
+		}
+		value, err := time.Parse(time.RFC3339, entry.Previous[field])
+		if err != nil {
+			return previous, fmt.Errorf("#%d: invalid previous %s: %w", entry.ID, field, err)
+		}
+		*date = &value
+		restored = true
+	}
+	if !restored {
+		return previous, fmt.Errorf("#%d set fields which were empty, they can't be emptied through the API", entry.ID)
+	}
+	return previous, nil
+}
+
+// NewRevertCmd creates the revert cmd
+func NewRevertCmd(api *ftapi.APIInterface, journal **historyJournal) *cobra.Command {
+	return &cobra.Command{
+		Use:   "revert id",
+		Short: "Undo a change listed by goft history",
+		Long: `Undo a change listed by goft history by sending its inverse.
+
+Added correction points are removed and removed ones are added back,
+user updates restore the previous email, names and kind, but not the password,
+cursus user updates restore the previous end date, blackhole date and grade when they were set.
+Creating users, closes and AGUs, enrolling in a cursus, setting images and raw requests can't be reverted.`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+				return err
+			}
+			if id, err := strconv.Atoi(args[0]); err != nil || id <= 0 {
+				return errors.New("id must be the number of a change listed by goft history")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, _ := strconv.Atoi(args[0])
+			entries, err := (*journal).Read()
+			if err != nil {
+				return err
+			}
+			entry, err := findHistoryEntry(entries, id)
+			if err != nil {
+				return err
+			}
+			// Sending the inverse twice would make the mistake again the other way
+			if by, ok := revertedBy(entries)[id]; ok {
+				return fmt.Errorf("#%d was already reverted by #%d", id, by)
+			}
+			if entry.Reverts != 0 {
+				return fmt.Errorf("#%d is the revert of #%d and can't be reverted, redo the change of #%d instead", id, entry.Reverts, entry.Reverts)
+			}
+			if reason := irreversibleReason(entry.Action); reason != "" {
+				return fmt.Errorf("#%d %s can't be reverted: %s", id, entry.Action, reason)
+			}
+			ctx := withRevert(cmd.Context(), id)
+			reason := fmt.Sprintf("Revert #%d: %s", id, entry.Reason)
+			switch entry.Action {
+			case actionAddPoints:
+				err = (*api).RemoveCorrectionPointsContext(ctx, entry.Login, entry.Points, reason)
+			case actionRemovePoints:
+				err = (*api).AddCorrectionPointsContext(ctx, entry.Login, entry.Points, reason)
+			case actionUpdateUser:
+				if _, ok := entry.Changes["password"]; ok {
+					cmd.PrintErrln("The previous password can't be restored")
+				}
+				previous := ftapi.User{
+					Email:     entry.Previous["email"],
+					FirstName: entry.Previous["first_name"],
+					LastName:  entry.Previous["last_name"],
+					Kind:      entry.Previous["kind"],
+				}
+				if previous.Email == "" && previous.FirstName == "" && previous.LastName == "" && previous.Kind == "" {
+					return fmt.Errorf("#%d only changed the password, nothing can be restored", id)
+				}
+				err = (*api).UpdateUserContext(ctx, entry.Login, &previous)
+			case actionUpdateCursus:
+				var previous ftapi.CursusUser
+				previous, err = previousCursusUser(entry)
+				if err != nil {
+					return err
+				}
+				err = (*api).UpdateCursusUserContext(ctx, entry.CursusUserID, &previous)
+			}
+			if err != nil {
+				return err
+			}
+			// The requests were only printed
+			if isDryRun(cmd) {
+				return nil
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Reverted #%d: %s %s\n", id, entry.Action, entry.describe())
+			return nil
+		},
+	}
+}
+
+var revertCmd = NewRevertCmd(&API, &history)
+
+func init() {
+	rootCmd.AddCommand(revertCmd)
+}
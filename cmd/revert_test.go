@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"goft/pkg/ftapi"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func executeRevert(t *testing.T, api ftapi.APIInterface, journal *historyJournal, id string) (string, string, error) {
+	stdout := bytes.NewBufferString("")
+	stderr := bytes.NewBufferString("")
+	revertCmd := NewRevertCmd(&api, &journal)
+	revertCmd.SetArgs([]string{id})
+	revertCmd.SetOut(stdout)
+	revertCmd.SetErr(stderr)
+	revertCmd.SilenceUsage = true
+	err := revertCmd.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestRevertPoints(t *testing.T) {
+	journal := newTestJournal(t)
+	mock := newJournalMockAPI()
+	client := journaledClient{mock, journal}
+	assert.Nil(t, client.AddCorrectionPointsContext(context.Background(), "spoody", 50, "typo"))
+
+	stdout, _, err := executeRevert(t, client, journal, "1")
+	assert.Nil(t, err)
+	assert.Equal(t, "Reverted #1: add_points +50 correction points: typo\n", stdout)
+	assert.Equal(t, uint(50), mock.removed["spoody"])
+
+	entries, err := journal.Read()
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, actionRemovePoints, entries[1].Action)
+	assert.Equal(t, 1, entries[1].Reverts)
+
+	_, _, err = executeRevert(t, client, journal, "1")
+	assert.EqualError(t, err, "#1 was already reverted by #2")
+	assert.Equal(t, uint(50), mock.removed["spoody"])
+	_, _, err = executeRevert(t, client, journal, "2")
+	assert.EqualError(t, err, "#2 is the revert of #1 and can't be reverted, redo the change of #1 instead")
+	assert.Equal(t, uint(50), mock.added["spoody"])
+	_, _, err = executeRevert(t, client, journal, "3")
+	assert.EqualError(t, err, "#3 not found in the history, see goft history")
+}
+
+func TestRevertUpdateUser(t *testing.T) {
+	journal := newTestJournal(t)
+	mock := newJournalMockAPI()
+	client := journaledClient{mock, journal}
+	assert.Nil(t, client.UpdateUser("spoody", &ftapi.User{Email: "new@1337.ma", Kind: "admin", Password: "hunter2"}))
+
+	_, stderr, err := executeRevert(t, client, journal, "1")
+	assert.Nil(t, err)
+	assert.Equal(t, "The previous password can't be restored\n", stderr)
+	assert.Equal(t, ftapi.User{Email: "old@1337.ma", Kind: "student"}, mock.updated["spoody"])
+}
+
+func TestRevertIrreversible(t *testing.T) {
+	journal := newTestJournal(t)
+	mock := newJournalMockAPI()
+	client := journaledClient{mock, journal}
+	assert.Nil(t, client.CreateClose(&ftapi.Close{Kind: "other", Reason: "left", User: &ftapi.User{Login: "spoody"}}))
+
+	_, _, err := executeRevert(t, client, journal, "1")
+	assert.EqualError(t, err, "#1 create_close can't be reverted: closes can't be deleted through the API")
+}
+
+func TestHistory(t *testing.T) {
+	journal := newTestJournal(t)
+	client := journaledClient{newJournalMockAPI(), journal}
+	assert.Nil(t, client.AddCorrectionPoints("spoody", 50, "typo"))
+	assert.Nil(t, client.RemoveCorrectionPointsContext(withRevert(context.Background(), 1), "spoody", 50, "Revert #1: typo"))
+
+	stdout := bytes.NewBufferString("")
+	historyCmd := NewHistoryCmd(&journal)
+	historyCmd.Flags().StringP("output", "o", "", outputFlagUsage)
+	historyCmd.SetArgs([]string{"-o", "template={{range .}}{{.id}} {{.action}} {{.login}}\n{{end}}"})
+	historyCmd.SetOut(stdout)
+	assert.Nil(t, historyCmd.Execute())
+	assert.Equal(t, "1 add_points spoody\n2 remove_points spoody\n", stdout.String())
+
+	stdout.Reset()
+	historyCmd = NewHistoryCmd(&journal)
+	historyCmd.SetArgs([]string{"-n", "1"})
+	historyCmd.SetOut(stdout)
+	assert.Nil(t, historyCmd.Execute())
+	assert.Regexp(t, `^#2 \S+ \S+ remove_points spoody -50 correction points: Revert #1: typo \(reverts #1\)\n$`, stdout.String())
+}
+
+func TestRevertDryRun(t *testing.T) {
+	journal := newTestJournal(t)
+	mock := newJournalMockAPI()
+	assert.Nil(t, journaledClient{mock, journal}.AddCorrectionPoints("spoody", 50, "typo"))
+
+	// In dry run mode the client isn't journaled and only prints the requests
+	var api ftapi.APIInterface = mock
+	stdout := bytes.NewBufferString("")
+	revertCmd := NewRevertCmd(&api, &journal)
+	revertCmd.Flags().Bool("dry-run", false, "")
+	revertCmd.SetArgs([]string{"1", "--dry-run"})
+	revertCmd.SetOut(stdout)
+	assert.Nil(t, revertCmd.Execute())
+	assert.Empty(t, stdout.String())
+
+	entries, err := journal.Read()
+	assert.Nil(t, err)
+	assert.Len(t, entries, 1)
+}
@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"goft/pkg/ftapi"
 	"os"
+	"os/signal"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -13,8 +17,30 @@ import (
 
 var (
 	cfgFile string
-	// API is used to interact with the 42 API
+	// profile is the name of the profile in use, empty when none is selected
+	profile string
+	// profileErr is why the selected profile couldn't be loaded, see checkProfile
+	profileErr error
+	// dryRun makes the API clients print write requests instead of sending them
+	dryRun bool
+	// API is used to interact with the 42 API, it uses either appAPI or userAPI depending on the command
 	API ftapi.APIInterface
+	// appAPI authenticates with the application's client credentials
+	appAPI ftapi.APIInterface
+	// userAPI authenticates as the user logged in with `goft auth login`
+	userAPI ftapi.APIInterface
+	// appCredentials is used for the client credentials flow
+	appCredentials *clientcredentials.Config
+	// appTokens provides the access tokens of the application, cached on disk between invocations
+	appTokens *ftapi.CachedTokenSource
+	// userTokens provides the access tokens of the logged in user
+	userTokens *ftapi.CachedTokenSource
+	// userOAuthConfig is used for the authorization code flow
+	userOAuthConfig *oauth2.Config
+	// auditLog records the write requests sent by both API clients
+	auditLog *ftapi.AuditLog
+	// history records the changes made with the profile in use, for goft history and goft revert
+	history *historyJournal
 	// Version the current used version
 	Version = "development-build"
 )
@@ -24,9 +50,33 @@ func NewRootCmd() *cobra.Command {
 	cmd := cobra.Command{
 		Use:   "goft",
 		Short: "CLI tool to interact with 42's API",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateOutputFormat(outputFormat(cmd)); err != nil {
+				return err
+			}
+			if err := checkProfile(cmd); err != nil {
+				return err
+			}
+			// Completion must not exit on missing settings, a cached token is enough
+			if cmd.Annotations[credentialsAnnotation] != "none" && !isCompletionCmd(cmd) {
+				requireCredentials(cmd)
+			}
+			if history != nil {
+				history.SetCommand(cmd, args)
+			}
+			return selectAPI(cmd)
+		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			// Remembered for completion, failing to write the cache doesn't fail the command
+			_ = rememberArgs(cmd, args, recentValuesPath())
+		},
 	}
-	defaultconf := os.Getenv("HOME") + "/.config/goft/config.yml"
+	defaultconf := goftDir() + "/config.yml"
 	cmd.PersistentFlags().StringVar(&cfgFile, "config", defaultconf, "config file")
+	cmd.PersistentFlags().StringVar(&profile, "profile", "", "Profile of the config file to use (defaults to $GOFT_PROFILE or the profile set with goft config profiles use)")
+	cmd.PersistentFlags().StringP("output", "o", "", outputFlagUsage)
+	cmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Print the requests that would modify data instead of sending them, lookups are still sent")
+	cmd.PersistentFlags().String("token", "", "Token used to send requests, either app or user (defaults to the command's preference)")
 	cmd.Version = Version
 	return &cmd
 }
@@ -35,8 +85,11 @@ var rootCmd = NewRootCmd()
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
+// Commands are cancelled on Ctrl-C through their context.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -49,33 +102,240 @@ func init() {
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	viper.SetConfigFile(cfgFile)
+	// Every setting can be set with a GOFT_ environment variable, like GOFT_CLIENT_SECRET or GOFT_SMTP_HOST
+	viper.SetEnvPrefix("goft")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
 
 	viper.SetDefault("token_endpoint", "https://api.intra.42.fr/oauth/token")
+	viper.SetDefault("authorize_endpoint", "https://api.intra.42.fr/oauth/authorize")
+	viper.SetDefault("redirect_uri", "http://localhost:4242/callback")
 	viper.SetDefault("api_endpoint", "https://api.intra.42.fr/v2")
 	viper.SetDefault("scopes", []string{"profile"})
+	viper.SetDefault("retry.max_attempts", ftapi.DefaultRetryPolicy.MaxAttempts)
+	viper.SetDefault("retry.max_elapsed", ftapi.DefaultRetryPolicy.MaxElapsedTime)
+	viper.SetDefault("retry.retry_writes", false)
+	viper.SetDefault("audit_log", goftDir()+"/audit.jsonl")
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		// Printed on stderr to keep stdout parsable by scripts and shell completion
+		_, _ = fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
 	}
 
-	requiredConfigs := []string{
-		"client_id",
-		"client_secret",
+	// The selected profile overrides the top level settings, which are shared by all profiles
+	if profile == "" {
+		profile = os.Getenv("GOFT_PROFILE")
 	}
-	for _, requiredConfig := range requiredConfigs {
-		if viper.GetString(requiredConfig) == "" {
-			_, _ = fmt.Fprintf(rootCmd.OutOrStderr(), "%s is required but not set in the config file\n", requiredConfig)
-			os.Exit(1)
+	if profile == "" {
+		profile = viper.GetString("profile")
+	}
+	// A missing profile is reported once the command is known, see checkProfile
+	profileErr = nil
+	if profile != "" {
+		if !viper.IsSet("profiles." + profile) {
+			profileErr = fmt.Errorf("profile %s not found in the config file, select another one with goft config profiles use", profile)
+		} else if err := viper.MergeConfigMap(viper.GetStringMap("profiles." + profile)); err != nil {
+			profileErr = fmt.Errorf("profile %s: %w", profile, err)
 		}
 	}
 
-	API = ftapi.NewFromCredentials(viper.GetString("api_endpoint"), &clientcredentials.Config{
+	appCredentials = &clientcredentials.Config{
 		ClientID:       viper.GetString("client_id"),
 		ClientSecret:   viper.GetString("client_secret"),
 		TokenURL:       viper.GetString("token_endpoint"),
 		Scopes:         viper.GetStringSlice("scopes"),
 		EndpointParams: nil,
 		AuthStyle:      oauth2.AuthStyleInParams,
+	}
+	appTokens = ftapi.NewCachedTokenSource(ftapi.NewTokenCache(profileDir()+"/token.json"), func(ctx context.Context, _ *oauth2.Token) (*oauth2.Token, error) {
+		secret, err := clientSecret()
+		if err != nil {
+			return nil, err
+		}
+		appCredentials.ClientSecret = secret
+		return appCredentials.Token(ctx)
+	})
+	userOAuthConfig = &oauth2.Config{
+		ClientID:     viper.GetString("client_id"),
+		ClientSecret: viper.GetString("client_secret"),
+		Endpoint: oauth2.Endpoint{
+			AuthURL:   viper.GetString("authorize_endpoint"),
+			TokenURL:  viper.GetString("token_endpoint"),
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+		RedirectURL: viper.GetString("redirect_uri"),
+		Scopes:      viper.GetStringSlice("scopes"),
+	}
+	userTokens = ftapi.NewCachedTokenSource(ftapi.NewTokenCache(profileDir()+"/user_token.json"), func(ctx context.Context, current *oauth2.Token) (*oauth2.Token, error) {
+		if current == nil || current.RefreshToken == "" {
+			return nil, errNotLoggedIn
+		}
+		secret, err := clientSecret()
+		if err != nil {
+			return nil, err
+		}
+		userOAuthConfig.ClientSecret = secret
+		// A token without access token is invalid, forcing the refresh
+		return userOAuthConfig.TokenSource(ctx, &oauth2.Token{RefreshToken: current.RefreshToken}).Token()
 	})
+	// Both tokens belong to the same application and share its quotas
+	limiter := ftapi.NewRateLimiter(ftapi.DefaultSecondlyLimit, ftapi.DefaultHourlyLimit)
+	auditLog = ftapi.NewAuditLog(viper.GetString("audit_log"), profile, auditOperator)
+	options := []ftapi.Option{ftapi.WithRetryPolicy(retryPolicy()), ftapi.WithRateLimiter(limiter), ftapi.WithAuditLog(auditLog)}
+	if dryRun {
+		options = append(options, ftapi.WithDryRun(os.Stdout))
+	}
+	appAPI = appClient{ftapi.NewFromTokenSource(context.Background(), viper.GetString("api_endpoint"), appTokens, options...)}
+	userAPI = ftapi.NewFromTokenSource(context.Background(), viper.GetString("api_endpoint"), userTokens, options...)
+	// The command line is set once the command is known, see PersistentPreRunE
+	history = newHistoryJournal(profileDir()+"/history.jsonl", "")
+	// Nothing changes in dry run mode, so there is nothing to revert
+	if !dryRun {
+		appAPI = journaledClient{appAPI, history}
+		userAPI = journaledClient{userAPI, history}
+	}
+	API = appAPI
+}
+
+var errNotLoggedIn = errors.New("not logged in, run `goft auth login` first")
+
+// appClient authenticates with the application's token, the application is not a user
+// so `me` refers to the logged in user even for commands using the application's token
+type appClient struct {
+	ftapi.APIInterface
+}
+
+// GetMe gets the logged in user
+func (c appClient) GetMe() (*ftapi.User, error) {
+	return c.GetMeContext(context.Background())
+}
+
+// GetMeContext is the same as GetMe but uses ctx for the underlying requests
+func (c appClient) GetMeContext(ctx context.Context) (*ftapi.User, error) {
+	if !userTokens.Cached() {
+		return nil, errNotLoggedIn
+	}
+	return userAPI.GetMeContext(ctx)
+}
+
+// auditOperator returns the login recorded in the audit log, the logged in user's or $USER when not logged in
+func auditOperator() string {
+	if userTokens.Cached() {
+		user, err := userAPI.GetMe()
+		if err == nil && user != nil && user.Login != "" {
+			return user.Login
+		}
+	}
+	return os.Getenv("USER")
+}
+
+// defaultLogin is the login used by commands about "my" data, me when logged in and $USER otherwise
+func defaultLogin() string {
+	if userTokens != nil && userTokens.Cached() {
+		return "me"
+	}
+	return os.Getenv("USER")
+}
+
+// credentialsAnnotation is set to none on commands that work without client credentials, like config commands
+const credentialsAnnotation = "goft/credentials"
+
+// requireCredentials exits if the client ID is not configured,
+// the client secret is only needed to fetch a new token, see clientSecret
+func requireCredentials(cmd *cobra.Command) {
+	if viper.GetString("client_id") == "" {
+		_, _ = fmt.Fprintln(cmd.OutOrStderr(), "client_id is required but not set in the config file nor with $GOFT_CLIENT_ID")
+		os.Exit(1)
+	}
+}
+
+// clientSecret returns the client secret, obtained from credential_command when it is not set.
+// It is called when a new token is requested, so the helper doesn't prompt while a cached token is valid
+func clientSecret() (string, error) {
+	secret := viper.GetString("client_secret")
+	if secret == "" && viper.GetString("credential_command") != "" {
+		var err error
+		secret, err = runCredentialCommand(viper.GetString("credential_command"), os.Stdin, os.Stderr)
+		if err != nil {
+			return "", err
+		}
+		viper.Set("client_secret", secret)
+	}
+	if secret == "" {
+		return "", errors.New("client_secret is required but not set in the config file nor with $GOFT_CLIENT_SECRET")
+	}
+	return secret, nil
+}
+
+// checkProfile returns the error of the selected profile, except for the config profiles commands
+// and the commands working without credentials, which must still work to select another profile
+func checkProfile(cmd *cobra.Command) error {
+	if profileErr == nil || cmd.Annotations[credentialsAnnotation] == "none" {
+		return nil
+	}
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		if c.Name() == "profiles" && c.Parent().Name() == "config" {
+			return nil
+		}
+	}
+	return profileErr
+}
+
+// isCompletionCmd returns true for the hidden commands used by shell completion scripts
+func isCompletionCmd(cmd *cobra.Command) bool {
+	return cmd.Name() == cobra.ShellCompRequestCmd || cmd.Name() == cobra.ShellCompNoDescRequestCmd
+}
+
+// isDryRun returns true if the --dry-run flag is set, for commands with side effects outside of the API
+func isDryRun(cmd *cobra.Command) bool {
+	flag := cmd.Flag("dry-run")
+	return flag != nil && flag.Value.String() == "true"
+}
+
+// tokenAnnotation is set on commands acting on behalf of the user, they use the user token when logged in
+const tokenAnnotation = "goft/token"
+
+// selectAPI sets API to the client matching the --token flag or the command's preference
+func selectAPI(cmd *cobra.Command) error {
+	token, _ := cmd.Flags().GetString("token")
+	switch token {
+	case "app":
+		API = appAPI
+	case "user":
+		if !userTokens.Cached() {
+			return errNotLoggedIn
+		}
+		API = userAPI
+	case "":
+		API = appAPI
+		if cmd.Annotations[tokenAnnotation] == "user" && userTokens.Cached() {
+			API = userAPI
+		}
+	default:
+		return fmt.Errorf("invalid token '%s', must be either app or user", token)
+	}
+	return nil
+}
+
+// goftDir returns the directory holding goft's config and state files
+func goftDir() string {
+	return os.Getenv("HOME") + "/.config/goft"
+}
+
+// profileDir returns the directory holding the state files of the profile in use, like cached tokens
+func profileDir() string {
+	if profile == "" {
+		return goftDir()
+	}
+	return goftDir() + "/profiles/" + profile
+}
+
+// retryPolicy builds the retry policy from the retry section of the config file
+func retryPolicy() ftapi.RetryPolicy {
+	policy := ftapi.DefaultRetryPolicy
+	policy.MaxAttempts = viper.GetInt("retry.max_attempts")
+	policy.MaxElapsedTime = viper.GetDuration("retry.max_elapsed")
+	policy.RetryNonIdempotent = viper.GetBool("retry.retry_writes")
+	return policy
 }
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -18,3 +19,25 @@ func TestNewRootCmd(t *testing.T) {
 	err := rootCmd.Execute()
 	assert.Nil(t, err)
 }
+
+func TestCheckProfile(t *testing.T) {
+	defer func(err error) { profileErr = err }(profileErr)
+	root := &cobra.Command{Use: "goft"}
+	config := &cobra.Command{Use: "config"}
+	profiles := &cobra.Command{Use: "profiles"}
+	use := &cobra.Command{Use: "use"}
+	version := &cobra.Command{Use: "version", Annotations: map[string]string{credentialsAnnotation: "none"}}
+	users := &cobra.Command{Use: "users"}
+	root.AddCommand(config, version, users)
+	config.AddCommand(profiles)
+	profiles.AddCommand(use)
+
+	profileErr = nil
+	assert.Nil(t, checkProfile(users))
+
+	profileErr = errors.New("profile paris not found in the config file")
+	assert.Equal(t, profileErr, checkProfile(users))
+	assert.Nil(t, checkProfile(use))
+	assert.Nil(t, checkProfile(profiles))
+	assert.Nil(t, checkProfile(version))
+}
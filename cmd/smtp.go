@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/gomail.v2"
+)
+
+// smtpSettings are the server and sender used by the commands sending emails
+type smtpSettings struct {
+	Host string
+	Port int
+	User string
+	Pass string
+	From string
+}
+
+// smtpDialer opens a connection to the SMTP server, it is replaced in tests
+type smtpDialer func(settings smtpSettings) (gomail.SendCloser, error)
+
+// dialSMTP connects to the SMTP server of settings
+func dialSMTP(settings smtpSettings) (gomail.SendCloser, error) {
+	return gomail.NewDialer(settings.Host, settings.Port, settings.User, settings.Pass).Dial()
+}
+
+// addSMTPFlags adds the flags overriding the smtp section of the config file
+func addSMTPFlags(cmd *cobra.Command) {
+	cmd.Flags().String("from-email", "", "Address to be used as a sender")
+	cmd.Flags().String("smtp-user", "", "SMTP username")
+	cmd.Flags().String("smtp-pass", "", "SMTP password")
+	cmd.Flags().String("smtp-host", "", "SMTP host")
+	cmd.Flags().Int("smtp-port", 25, "SMTP port")
+}
+
+// readSMTPSettings reads the flags added by addSMTPFlags, defaulting to the smtp section of the config file
+func readSMTPSettings(cmd *cobra.Command) smtpSettings {
+	return smtpSettings{
+		Host: stringSetting(cmd, "smtp-host", "smtp.host"),
+		Port: intSetting(cmd, "smtp-port", "smtp.port"),
+		User: stringSetting(cmd, "smtp-user", "smtp.user"),
+		Pass: stringSetting(cmd, "smtp-pass", "smtp.pass"),
+		From: stringSetting(cmd, "from-email", "smtp.from"),
+	}
+}
+
+// validate returns an error if a setting is missing
+func (s smtpSettings) validate() error {
+	if s.User == "" || s.Pass == "" || s.Host == "" || s.Port <= 0 || s.From == "" {
+		return errors.New("missing SMTP settings, set them with flags or in the smtp section of the config file")
+	}
+	return nil
+}
+
+// stringSetting returns the value of flag if it is set, the value of key in the config file otherwise
+func stringSetting(cmd *cobra.Command, flag string, key string) string {
+	if !cmd.Flags().Changed(flag) && viper.IsSet(key) {
+		return viper.GetString(key)
+	}
+	value, _ := cmd.Flags().GetString(flag)
+	return value
+}
+
+// intSetting is the same as stringSetting for int flags
+func intSetting(cmd *cobra.Command, flag string, key string) int {
+	if !cmd.Flags().Changed(flag) && viper.IsSet(key) {
+		return viper.GetInt(key)
+	}
+	value, _ := cmd.Flags().GetInt(flag)
+	return value
+}
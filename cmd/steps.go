@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// step is one of the requests of a command sending several of them,
+// Undo compensates Do once it succeeded and is nil when nothing can compensate it
+type step struct {
+	// Name describes the step, like "remove 7 correction points from spoody"
+	Name string
+	Do   func(ctx context.Context) error
+	Undo func(ctx context.Context) error
+}
+
+// stepsError is returned by runSteps when a step failed
+type stepsError struct {
+	// Failed is the name of the step that failed
+	Failed string
+	Err    error
+	// Compensated is true when every step done before the failure was undone
+	Compensated bool
+}
+
+func (e *stepsError) Error() string {
+	if e.Compensated {
+		return fmt.Sprintf("failed to %s, the previous steps were undone: %s", e.Failed, e.Err)
+	}
+	return fmt.Sprintf("failed to %s, the previous steps could not all be undone: %s", e.Failed, e.Err)
+}
+
+func (e *stepsError) Unwrap() error {
+	return e.Err
+}
+
+// runSteps runs steps in order, when one fails the steps already done are undone from the last one.
+// Every step done or undone is reported to w
+func runSteps(ctx context.Context, w io.Writer, steps []step) error {
+	for i, current := range steps {
+		err := current.Do(ctx)
+		if err == nil {
+			_, _ = fmt.Fprintf(w, "Done: %s\n", current.Name)
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "Failed: %s: %s\n", current.Name, err)
+		compensated := true
+		for j := i - 1; j >= 0; j-- {
+			done := steps[j]
+			if done.Undo == nil {
+				compensated = false
+				_, _ = fmt.Fprintf(w, "Can't undo: %s\n", done.Name)
+				continue
+			}
+			// The context may be the reason of the failure, the compensation must still be sent
+			if undoErr := done.Undo(context.Background()); undoErr != nil {
+				compensated = false
+				_, _ = fmt.Fprintf(w, "Failed to undo: %s: %s\n", done.Name, undoErr)
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "Undone: %s\n", done.Name)
+		}
+		return &stepsError{Failed: current.Name, Err: err, Compensated: compensated}
+	}
+	return nil
+}
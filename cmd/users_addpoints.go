@@ -14,6 +14,10 @@ func NewAddPointsCmd(api *ftapi.APIInterface) *cobra.Command {
 		Use:   "add-points login points reason",
 		Short: "Add correction points to user",
 		Long: "This command requires the Advanced tutor role",
+		Annotations: map[string]string{
+			argsAnnotation: "login",
+		},
+		ValidArgsFunction: completeArgs(api),
 		Args: func(cmd *cobra.Command, args []string) error {
 			if err := cobra.ExactArgs(3)(cmd, args); err != nil {
 				return err
@@ -25,8 +29,12 @@ func NewAddPointsCmd(api *ftapi.APIInterface) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			login, err := resolveLogin(cmd.Context(), *api, args[0])
+			if err != nil {
+				return err
+			}
 			points, _ := strconv.ParseUint(args[1], 10, 0)
-			return (*api).AddCorrectionPoints(args[0], uint(points), args[2])
+			return (*api).AddCorrectionPointsContext(cmd.Context(), login, uint(points), args[2])
 		},
 	}
 }
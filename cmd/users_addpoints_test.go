@@ -2,67 +2,24 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"goft/pkg/ftapi"
-	"io"
 	"io/ioutil"
-	"net/http"
-	"os"
 	"testing"
 )
 
 type addPointsMockAPI struct {
+	baseMockAPI
 	t *testing.T
 }
-func (m *addPointsMockAPI) Get(url string) (*http.Response, error) {
-	return nil, nil
-}
-func (m *addPointsMockAPI) Delete(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *addPointsMockAPI) Post(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *addPointsMockAPI) PostJSON(url string, data interface{}) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *addPointsMockAPI) Patch(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *addPointsMockAPI) PatchJSON(url string, data interface{}) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *addPointsMockAPI) SetUserImage(login string, img *os.File) error {
-	return nil
-}
-func (m *addPointsMockAPI) CreateUser(user *ftapi.User, campusID int) error {
-	return nil
-}
-func (m *addPointsMockAPI) CreateClose(close *ftapi.Close) error {
-	return nil
-}
-func (m *addPointsMockAPI) GetUserByLogin(login string) (*ftapi.User, error) {
-	return nil, nil
-}
-func (m *addPointsMockAPI) UpdateUser(login string, data *ftapi.User) error  {
-	return nil
-}
-func (m *addPointsMockAPI) AddCorrectionPoints(login string, points uint, reason string) error{
+func (m *addPointsMockAPI) AddCorrectionPointsContext(ctx context.Context, login string, points uint, reason string) error{
 	assert.Equal(m.t, "spoody", login)
 	assert.Equal(m.t, uint(5), points)
 	assert.Equal(m.t, "Testing purposes", reason)
 	return nil
 }
-func (m *addPointsMockAPI) RemoveCorrectionPoints(login string, points uint, reason string) error{
-	return nil
-}
-func (m *addPointsMockAPI) GetUserAgus(login string) ([]ftapi.Agu, error) {
-	return nil, nil
-}
-func (m *addPointsMockAPI) CreateFreePastAgu(login string, duration int, reason string) error {
-	return nil
-}
 
 func TestNewAddPointsCmd(t *testing.T) {
 	var api ftapi.APIInterface = &addPointsMockAPI{t: t}
@@ -2,36 +2,79 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
 	"goft/pkg/ftapi"
+	"os"
 	"strconv"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
+// isValidUserKind returns true if kind is a kind of user that can be created
+func isValidUserKind(kind string) bool {
+	return kind == "admin" || kind == "student" || kind == "external"
+}
+
 // NewUserCreateCmd create the users create cmd
 func NewUserCreateCmd(api *ftapi.APIInterface) *cobra.Command {
 	cmd := cobra.Command{
-		Use:   "create email first_name last_name kind campus_id",
+		Use:   "create email first_name last_name kind [campus]",
 		Short: "Create a new user",
 		Long: `This command requires the Advanced tutor role
 No password is set, the user should reset his password using the web interface.
 
-kind must be either admin, student or external.`,
+kind must be either admin, student or external.
+campus is the ID, name or slug of the campus, it defaults to the campus_id of the config file.
+
+With --from, the users are read from a csv or json roster instead of the arguments.
+The csv file starts with a header naming its columns: email, first_name, last_name, kind
+and optionally campus_id, login and image, a path to the profile image to set.
+A json roster is an array of objects with the same keys.
+Every row is validated first, users whose login or email already exists are skipped,
+and a csv report with the created IDs and the errors of each row is written to --report.`,
+		Annotations: map[string]string{
+			argsAnnotation: ",,,,campus",
+		},
+		ValidArgsFunction: completeArgs(api),
 		Args: func(cmd *cobra.Command, args []string) error {
-			if err := cobra.ExactArgs(5)(cmd, args); err != nil {
+			if from, _ := cmd.Flags().GetString("from"); from != "" {
+				return cobra.NoArgs(cmd, args)
+			}
+			expectedArgs := cobra.ExactArgs(5)
+			if viper.GetInt("campus_id") > 0 {
+				expectedArgs = cobra.RangeArgs(4, 5)
+			}
+			if err := expectedArgs(cmd, args); err != nil {
 				return err
 			}
-			if args[3] != "admin" && args[3] != "student" && args[3] != "external" {
+			if !isValidUserKind(args[3]) {
 				return errors.New("kind must be admin, student or external")
 			}
-			campusID, err := strconv.Atoi(args[4])
-			if err != nil || campusID <= 0 {
-				return errors.New("invalid campus_id")
+			// Names and slugs are resolved when running the command
+			if len(args) == 5 {
+				campusID, err := strconv.Atoi(args[4])
+				if args[4] == "" || err == nil && campusID <= 0 {
+					return errors.New("invalid campus_id")
+				}
 			}
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			campusID, _ := strconv.Atoi(args[4])
+			if from, _ := cmd.Flags().GetString("from"); from != "" {
+				return createUsersFromRoster(cmd, *api, from)
+			}
+			campusID := viper.GetInt("campus_id")
+			if len(args) == 5 {
+				var err error
+				campusID, err = resolveCampusID(cmd.Context(), *api, args[4])
+				if errors.Is(err, ftapi.ErrNotFound) {
+					return errors.New("invalid campus_id")
+				}
+				if err != nil {
+					return err
+				}
+			}
 			user := ftapi.User{
 				Email:     args[0],
 				FirstName: args[1],
@@ -42,7 +85,7 @@ kind must be either admin, student or external.`,
 			if login != "" {
 				user.Login = login
 			}
-			err := (*api).CreateUser(&user, campusID)
+			err := (*api).CreateUserContext(cmd.Context(), &user, campusID)
 			if err != nil {
 				return err
 			}
@@ -51,8 +94,48 @@ kind must be either admin, student or external.`,
 		},
 	}
 	cmd.Flags().String("login", "", "Set a custom login, leave empty to let the intra generate one")
+	cmd.Flags().String("from", "", "Create the users of a csv or json roster file")
+	cmd.Flags().String("report", "", "File where the csv report of --from is written (defaults to stdout)")
+	cmd.Flags().Int("concurrency", 4, "Number of users of --from created at the same time")
 	return &cmd
 }
+
+// createUsersFromRoster creates the users of the roster at path and writes the report
+func createUsersFromRoster(cmd *cobra.Command, api ftapi.APIInterface, path string) error {
+	entries, err := readRoster(path)
+	if err != nil {
+		return err
+	}
+	invalid := validateRosterEntries(entries, viper.GetInt("campus_id"))
+	if invalid > 0 {
+		cmd.PrintErrf("%d of %d rows are invalid and will be skipped\n", invalid, len(entries))
+	}
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	results := createRosterUsers(cmd.Context(), api, entries, concurrency)
+
+	report := cmd.OutOrStdout()
+	if reportPath, _ := cmd.Flags().GetString("report"); reportPath != "" {
+		file, err := os.Create(reportPath)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		report = file
+	}
+	err = writeRosterReport(report, results)
+	if err != nil {
+		return err
+	}
+	counts := map[string]int{}
+	for _, result := range results {
+		counts[result.Status]++
+	}
+	cmd.PrintErrf("%d created, %d skipped, %d failed\n", counts[rosterCreated], counts[rosterSkipped], counts[rosterFailed])
+	if counts[rosterFailed] > 0 {
+		return fmt.Errorf("failed creating %d of %d users", counts[rosterFailed], len(results))
+	}
+	return nil
+}
 var userCreateCmd = NewUserCreateCmd(&API)
 
 func init() {
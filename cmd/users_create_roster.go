@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"goft/pkg/ftapi"
+	"io"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// rosterEntry is a user to create, read from a row of a roster file
+type rosterEntry struct {
+	// Line is the line of the csv file or the index of the json array, starting at 1
+	Line      int    `json:"-"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Kind      string `json:"kind"`
+	CampusID  int    `json:"campus_id"`
+	Login     string `json:"login"`
+	Image     string `json:"image"`
+	// Err is set when the row couldn't be parsed
+	Err error `json:"-"`
+}
+
+// Statuses of a roster entry once processed
+const (
+	rosterCreated = "created"
+	rosterSkipped = "skipped"
+	rosterFailed  = "failed"
+)
+
+// rosterResult is the outcome of creating a roster entry, written as a row of the report
+type rosterResult struct {
+	Line   int
+	Email  string
+	Login  string
+	ID     int
+	Status string
+	Error  string
+}
+
+// rosterColumns are the columns of a csv roster, only email, first_name, last_name and kind are required
+var rosterColumns = []string{"email", "first_name", "last_name", "kind", "campus_id", "login", "image"}
+
+// readRoster reads the users of a csv or json roster file, the format is chosen from the extension.
+// Relative image paths are relative to the roster file
+func readRoster(path string) ([]rosterEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var entries []rosterEntry
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		entries, err = readJSONRoster(file)
+	} else {
+		entries, err = readCSVRoster(file)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if entries[i].Image != "" && !filepath.IsAbs(entries[i].Image) {
+			entries[i].Image = filepath.Join(filepath.Dir(path), entries[i].Image)
+		}
+	}
+	return entries, nil
+}
+
+// readJSONRoster reads an array of users with the same keys as the csv columns
+func readJSONRoster(r io.Reader) ([]rosterEntry, error) {
+	var entries []rosterEntry
+	err := json.NewDecoder(r).Decode(&entries)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].Line = i + 1
+	}
+	return entries, nil
+}
+
+// readCSVRoster reads a csv file whose first line is a header naming the rosterColumns
+func readCSVRoster(r io.Reader) ([]rosterEntry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading roster header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range rosterColumns[:4] {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("roster is missing the %s column", required)
+		}
+	}
+	var entries []rosterEntry
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		entry := rosterEntry{
+			Line:      line,
+			Email:     value("email"),
+			FirstName: value("first_name"),
+			LastName:  value("last_name"),
+			Kind:      value("kind"),
+			Login:     value("login"),
+			Image:     value("image"),
+		}
+		if campusID := value("campus_id"); campusID != "" {
+			entry.CampusID, err = strconv.Atoi(campusID)
+			if err != nil {
+				entry.Err = errors.New("invalid campus_id")
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// validateRosterEntries checks every entry, setting their Err, and defaults their campus to campusID.
+// It returns the number of invalid entries
+func validateRosterEntries(entries []rosterEntry, campusID int) int {
+	invalid := 0
+	seen := map[string]int{}
+	for i := range entries {
+		entry := &entries[i]
+		if entry.CampusID == 0 {
+			entry.CampusID = campusID
+		}
+		if entry.Err == nil {
+			entry.Err = validateRosterEntry(entry)
+		}
+		for _, key := range []string{"email:" + strings.ToLower(entry.Email), "login:" + entry.Login} {
+			if entry.Err != nil || key == "login:" {
+				continue
+			}
+			if line, ok := seen[key]; ok {
+				entry.Err = fmt.Errorf("duplicate of line %d", line)
+				continue
+			}
+			seen[key] = entry.Line
+		}
+		if entry.Err != nil {
+			invalid++
+		}
+	}
+	return invalid
+}
+
+// validateRosterEntry returns an error if the entry can't be used to create a user
+func validateRosterEntry(entry *rosterEntry) error {
+	if _, err := mail.ParseAddress(entry.Email); err != nil {
+		return fmt.Errorf("invalid email '%s'", entry.Email)
+	}
+	if entry.FirstName == "" || entry.LastName == "" {
+		return errors.New("first_name and last_name are required")
+	}
+	if !isValidUserKind(entry.Kind) {
+		return errors.New("kind must be admin, student or external")
+	}
+	if entry.CampusID <= 0 {
+		return errors.New("invalid campus_id")
+	}
+	if entry.Image != "" {
+		if err := validateImage(entry.Image); err != nil {
+			return fmt.Errorf("image: %w", err)
+		}
+	}
+	return nil
+}
+
+// createRosterUsers creates the valid entries that don't exist yet using concurrency workers,
+// the requests share the API's rate limiter. Results are in the same order as entries
+func createRosterUsers(ctx context.Context, api ftapi.APIInterface, entries []rosterEntry, concurrency int) []rosterResult {
+	results := make([]rosterResult, len(entries))
+	indexes := make(chan int)
+	wg := sync.WaitGroup{}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = createRosterUser(ctx, api, &entries[i])
+			}
+		}()
+	}
+	for i := range entries {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return results
+}
+
+// createRosterUser creates the user of entry unless it is invalid or already exists
+func createRosterUser(ctx context.Context, api ftapi.APIInterface, entry *rosterEntry) rosterResult {
+	result := rosterResult{Line: entry.Line, Email: entry.Email, Login: entry.Login}
+	fail := func(err error) rosterResult {
+		result.Status = rosterFailed
+		result.Error = err.Error()
+		return result
+	}
+	if entry.Err != nil {
+		return fail(entry.Err)
+	}
+	if err := ctx.Err(); err != nil {
+		return fail(err)
+	}
+	existing, err := findExistingUser(ctx, api, entry)
+	if err != nil {
+		return fail(err)
+	}
+	if existing != nil {
+		result.Status = rosterSkipped
+		result.ID = existing.ID
+		result.Login = existing.Login
+		result.Error = "already exists"
+		return result
+	}
+	user := ftapi.User{
+		Login:     entry.Login,
+		Email:     entry.Email,
+		FirstName: entry.FirstName,
+		LastName:  entry.LastName,
+		Kind:      entry.Kind,
+	}
+	err = api.CreateUserContext(ctx, &user, entry.CampusID)
+	if err != nil {
+		return fail(err)
+	}
+	result.Status = rosterCreated
+	result.ID = user.ID
+	result.Login = user.Login
+	if entry.Image == "" {
+		return result
+	}
+	img, err := os.Open(entry.Image)
+	if err == nil {
+		err = api.SetUserImageContext(ctx, user.Login, img)
+		img.Close()
+	}
+	if err != nil {
+		// The user is created, only the image is missing
+		result.Error = "setting image: " + err.Error()
+	}
+	return result
+}
+
+// findExistingUser looks for a user with the login or the email of entry, it returns nil if there is none
+func findExistingUser(ctx context.Context, api ftapi.APIInterface, entry *rosterEntry) (*ftapi.User, error) {
+	if entry.Login != "" {
+		user, err := api.GetUserByLoginContext(ctx, entry.Login)
+		if err != nil && !errors.Is(err, ftapi.ErrNotFound) {
+			return nil, err
+		}
+		if err == nil && user != nil {
+			return user, nil
+		}
+	}
+	user, err := api.GetUserByEmailContext(ctx, entry.Email)
+	if err != nil && !errors.Is(err, ftapi.ErrNotFound) {
+		return nil, err
+	}
+	if err == nil && user != nil {
+		return user, nil
+	}
+	return nil, nil
+}
+
+// writeRosterReport writes the results as csv
+func writeRosterReport(w io.Writer, results []rosterResult) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"line", "email", "login", "id", "status", "error"})
+	for _, result := range results {
+		id := ""
+		if result.ID != 0 {
+			id = strconv.Itoa(result.ID)
+		}
+		_ = cw.Write([]string{strconv.Itoa(result.Line), result.Email, result.Login, id, result.Status, result.Error})
+	}
+	cw.Flush()
+	return cw.Error()
+}
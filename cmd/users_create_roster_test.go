@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"goft/pkg/ftapi"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type rosterMockAPI struct {
+	baseMockAPI
+	mu      sync.Mutex
+	created []string
+}
+
+func (m *rosterMockAPI) GetUserByLoginContext(ctx context.Context, login string) (*ftapi.User, error) {
+	if login == "spoody" {
+		return &ftapi.User{ID: 1, Login: "spoody"}, nil
+	}
+	return nil, &ftapi.APIError{StatusCode: 404}
+}
+
+func (m *rosterMockAPI) GetUserByEmailContext(ctx context.Context, email string) (*ftapi.User, error) {
+	if email == "taken@1337.ma" {
+		return &ftapi.User{ID: 2, Login: "taken"}, nil
+	}
+	return nil, ftapi.ErrNotFound
+}
+
+func (m *rosterMockAPI) CreateUserContext(ctx context.Context, user *ftapi.User, campusID int) error {
+	if user.Email == "broken@1337.ma" {
+		return errors.New("internal error")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.created = append(m.created, user.Email)
+	user.ID = 100 + len(m.created)
+	if user.Login == "" {
+		user.Login = strings.Split(user.Email, "@")[0]
+	}
+	return nil
+}
+
+const testRoster = `email,first_name,last_name,kind,campus_id,login
+new@1337.ma,New,User,student,21,
+spoody@1337.ma,Mehdi,Bounya,admin,21,spoody
+taken@1337.ma,Taken,User,student,,
+broken@1337.ma,Broken,User,student,21,
+invalid,Invalid,Email,student,21,
+kind@1337.ma,Invalid,Kind,staff,21,
+campus@1337.ma,Invalid,Campus,student,abc,
+new@1337.ma,Duplicate,User,student,21,
+`
+
+func TestReadRoster(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "roster.csv")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(testRoster), 0600))
+	entries, err := readRoster(path)
+	assert.Nil(t, err)
+	assert.Len(t, entries, 8)
+	assert.Equal(t, rosterEntry{Line: 3, Email: "spoody@1337.ma", FirstName: "Mehdi", LastName: "Bounya", Kind: "admin", CampusID: 21, Login: "spoody"}, entries[1])
+	assert.EqualError(t, entries[6].Err, "invalid campus_id")
+
+	path = filepath.Join(t.TempDir(), "roster.json")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(`[{"email":"new@1337.ma","first_name":"New","last_name":"User","kind":"student","image":"new.png"}]`), 0600))
+	entries, err = readRoster(path)
+	assert.Nil(t, err)
+	assert.Equal(t, []rosterEntry{{Line: 1, Email: "new@1337.ma", FirstName: "New", LastName: "User", Kind: "student", Image: filepath.Join(filepath.Dir(path), "new.png")}}, entries)
+
+	_, err = readCSVRoster(strings.NewReader("email,first_name\n"))
+	assert.EqualError(t, err, "roster is missing the last_name column")
+}
+
+func TestCreateRosterUsers(t *testing.T) {
+	entries, err := readCSVRoster(strings.NewReader(testRoster))
+	assert.Nil(t, err)
+	assert.Equal(t, 4, validateRosterEntries(entries, 42))
+	assert.Equal(t, 42, entries[2].CampusID)
+
+	api := &rosterMockAPI{}
+	results := createRosterUsers(context.Background(), api, entries, 3)
+	assert.Equal(t, []string{"new@1337.ma"}, api.created)
+
+	report := bytes.NewBufferString("")
+	assert.Nil(t, writeRosterReport(report, results))
+	assert.Equal(t, `line,email,login,id,status,error
+2,new@1337.ma,new,101,created,
+3,spoody@1337.ma,spoody,1,skipped,already exists
+4,taken@1337.ma,taken,2,skipped,already exists
+5,broken@1337.ma,,,failed,internal error
+6,invalid,,,failed,invalid email 'invalid'
+7,kind@1337.ma,,,failed,"kind must be admin, student or external"
+8,campus@1337.ma,,,failed,invalid campus_id
+9,new@1337.ma,,,failed,duplicate of line 2
+`, report.String())
+}
+
+func TestCreateUserFromRoster(t *testing.T) {
+	dir := t.TempDir()
+	rosterPath := filepath.Join(dir, "roster.csv")
+	reportPath := filepath.Join(dir, "report.csv")
+	assert.Nil(t, ioutil.WriteFile(rosterPath, []byte("email,first_name,last_name,kind,campus_id\nnew@1337.ma,New,User,student,21\n"), 0600))
+
+	var api ftapi.APIInterface = &rosterMockAPI{}
+	stderr := bytes.NewBufferString("")
+	createCmd := NewUserCreateCmd(&api)
+	createCmd.SetArgs([]string{"--from", rosterPath, "--report", reportPath})
+	createCmd.SetErr(stderr)
+	assert.Nil(t, createCmd.Execute())
+	assert.Equal(t, "1 created, 0 skipped, 0 failed\n", stderr.String())
+	report, err := ioutil.ReadFile(reportPath)
+	assert.Nil(t, err)
+	assert.Equal(t, "line,email,login,id,status,error\n2,new@1337.ma,new,101,created,\n", string(report))
+}
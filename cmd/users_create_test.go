@@ -2,41 +2,22 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"goft/pkg/ftapi"
-	"io"
 	"io/ioutil"
 	"net/http"
-	"os"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 )
 
 type mockAPI struct {
+	baseMockAPI
 	t *testing.T
 }
-func (m *mockAPI) Get(url string) (*http.Response, error) {
-	return nil, nil
-}
-func (m *mockAPI) Delete(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *mockAPI) Post(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *mockAPI) PostJSON(url string, data interface{}) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *mockAPI) Patch(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *mockAPI) PatchJSON(url string, data interface{}) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *mockAPI) SetUserImage(login string, img *os.File) error {
-	return nil
-}
-func (m *mockAPI) CreateUser(user *ftapi.User, campusID int) error {
+func (m *mockAPI) CreateUserContext(ctx context.Context, user *ftapi.User, campusID int) error {
 	if user.Email == "spoody@with.login" {
 		assert.Equal(m.t, "spoody", user.Login)
 		assert.Equal(m.t, "Mehdi", user.FirstName)
@@ -55,26 +36,12 @@ func (m *mockAPI) CreateUser(user *ftapi.User, campusID int) error {
 
 	return nil
 }
-func (m *mockAPI) CreateClose(close *ftapi.Close) error {
-	return nil
-}
-func (m *mockAPI) GetUserByLogin(login string) (*ftapi.User, error) {
-	return nil, nil
-}
-func (m *mockAPI) UpdateUser(login string, data *ftapi.User) error  {
-	return nil
-}
-func (m *mockAPI) AddCorrectionPoints(login string, points uint, reason string) error{
-	return nil
-}
-func (m *mockAPI) RemoveCorrectionPoints(login string, points uint, reason string) error{
-	return nil
-}
-func (m *mockAPI) GetUserAgus(login string) ([]ftapi.Agu, error) {
-	return nil, nil
-}
-func (m *mockAPI) CreateFreePastAgu(login string, duration int, reason string) error {
-	return nil
+func (m *mockAPI) ListCampusesContext(ctx context.Context, params url.Values) *ftapi.Pager {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		_, _ = rw.Write([]byte(`[{"id":21,"name":"Benguerir"}]`))
+	}))
+	m.t.Cleanup(server.Close)
+	return ftapi.New(server.URL, server.Client(), ftapi.WithRateLimiter(nil)).ListCampusesContext(ctx, params)
 }
 
 
@@ -165,4 +132,35 @@ func TestCreateUserWithInvalidCampusId(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Equal(t, "invalid campus_id", err.Error())
 	assert.Equal(t, "Error: invalid campus_id\n", string(stderrText))
-}
\ No newline at end of file
+}
+
+func TestCreateUserWithCampusName(t *testing.T) {
+	var api ftapi.APIInterface = &mockAPI{t: t}
+	stdout := bytes.NewBufferString("")
+	createCmd := NewUserCreateCmd(&api)
+	createCmd.SetArgs([]string{"spoody@without.login", "Mehdi", "Bounya", "admin", "benguerir"})
+	createCmd.SetOut(stdout)
+	assert.Nil(t, createCmd.Execute())
+	assert.Equal(t, "User created\n", stdout.String())
+}
+
+func TestCreateUserWithUnknownCampusName(t *testing.T) {
+	var api ftapi.APIInterface = &mockAPI{t: t}
+	stderr := bytes.NewBufferString("")
+	createCmd := NewUserCreateCmd(&api)
+	createCmd.SetArgs([]string{"spoody@without.login", "Mehdi", "Bounya", "admin", "atlantis"})
+	createCmd.SetOut(bytes.NewBufferString(""))
+	createCmd.SetErr(stderr)
+	err := createCmd.Execute()
+	assert.EqualError(t, err, "invalid campus_id")
+	assert.Equal(t, "Error: invalid campus_id\n", stderr.String())
+}
+
+func TestCreateUserWithZeroCampusId(t *testing.T) {
+	var api ftapi.APIInterface = &mockAPI{t: t}
+	createCmd := NewUserCreateCmd(&api)
+	createCmd.SetArgs([]string{"spoody@without.login", "Mehdi", "Bounya", "admin", "0"})
+	createCmd.SetOut(bytes.NewBufferString(""))
+	createCmd.SetErr(bytes.NewBufferString(""))
+	assert.EqualError(t, createCmd.Execute(), "invalid campus_id")
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"goft/pkg/ftapi"
+	"io"
+	"strconv"
 
 	"github.com/spf13/cobra"
 )
@@ -41,22 +43,59 @@ Pool Month/Year: %s/%s
 	return output
 }
 
+// usersTable returns the table and csv view of users
+func usersTable(users ...*ftapi.User) table {
+	t := table{Header: []string{"ID", "LOGIN", "EMAIL", "FIRST NAME", "LAST NAME", "STAFF", "CORRECTION POINTS", "WALLET", "POOL", "CAMPUS"}}
+	for _, user := range users {
+		campus := ""
+		if primaryCampus := user.GetPrimaryCampus(); primaryCampus != nil {
+			campus = primaryCampus.Name
+		}
+		t.Rows = append(t.Rows, []string{
+			strconv.Itoa(user.ID),
+			user.Login,
+			user.Email,
+			user.FirstName,
+			user.LastName,
+			strconv.FormatBool(user.IsStaff),
+			strconv.Itoa(user.CorrectionPoints),
+			strconv.Itoa(user.Wallet),
+			user.PoolMonth + "/" + user.PoolYear,
+			campus,
+		})
+	}
+	return t
+}
+
 // NewGetUserCmd create the get user cmd
 func NewGetUserCmd(api *ftapi.APIInterface) *cobra.Command {
 	return &cobra.Command{
 		Use:   "get login",
 		Short: "Get details about a user",
+		Annotations: map[string]string{
+			argsAnnotation: "login",
+		},
+		ValidArgsFunction: completeArgs(api),
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error{
-			user, err := (*api).GetUserByLogin(args[0])
+			login, err := resolveLogin(cmd.Context(), *api, args[0])
+			if err != nil {
+				return err
+			}
+			user, err := (*api).GetUserByLoginContext(cmd.Context(), login)
 			if err != nil {
 				return err
 			}
 			if user == nil {
 				return errors.New("failed getting user")
 			}
-			cmd.Print(formatText(user))
-			return nil
+			return printOutput(cmd, output{
+				Data:  user,
+				Table: usersTable(user),
+				Text: func(w io.Writer) {
+					_, _ = fmt.Fprint(w, formatText(user))
+				},
+			})
 		},
 	}
 }
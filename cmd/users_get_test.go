@@ -2,47 +2,19 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"goft/pkg/ftapi"
-	"io"
 	"io/ioutil"
-	"net/http"
-	"os"
 	"testing"
 )
 
 type usersGetMockAPI struct {
+	baseMockAPI
 	t *testing.T
 }
-func (m *usersGetMockAPI) Get(url string) (*http.Response, error) {
-	return nil, nil
-}
-func (m *usersGetMockAPI) Post(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *usersGetMockAPI) Delete(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *usersGetMockAPI) PostJSON(url string, data interface{}) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *usersGetMockAPI) Patch(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *usersGetMockAPI) PatchJSON(url string, data interface{}) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *usersGetMockAPI) SetUserImage(login string, img *os.File) error {
-	return nil
-}
-func (m *usersGetMockAPI) CreateUser(user *ftapi.User, campusID int) error {
-	return nil
-}
-func (m *usersGetMockAPI) CreateClose(close *ftapi.Close) error {
-	return nil
-}
-func (m *usersGetMockAPI) GetUserByLogin(login string) (*ftapi.User, error) {
+func (m *usersGetMockAPI) GetUserByLoginContext(ctx context.Context, login string) (*ftapi.User, error) {
 	campus := ftapi.Campus{
 		ID:          42,
 		Name:        "Los Santos",
@@ -72,21 +44,6 @@ func (m *usersGetMockAPI) GetUserByLogin(login string) (*ftapi.User, error) {
 		Wallet: 1337,
 	}, nil
 }
-func (m *usersGetMockAPI) UpdateUser(login string, data *ftapi.User) error  {
-	return nil
-}
-func (m *usersGetMockAPI) AddCorrectionPoints(login string, points uint, reason string) error{
-	return nil
-}
-func (m *usersGetMockAPI) RemoveCorrectionPoints(login string, points uint, reason string) error{
-	return nil
-}
-func (m *usersGetMockAPI) GetUserAgus(login string) ([]ftapi.Agu, error) {
-	return nil, nil
-}
-func (m *usersGetMockAPI) CreateFreePastAgu(login string, duration int, reason string) error {
-	return nil
-}
 
 func TestNewGetUserCmd(t *testing.T) {
 	var api ftapi.APIInterface = &usersGetMockAPI{t: t}
@@ -129,4 +86,17 @@ Correction points: 4
 Wallet: 1337
 Pool Month/Year: April/2019
 `, string(out))
-}
\ No newline at end of file
+}
+
+func TestGetUserCmdOutputTemplate(t *testing.T) {
+	var api ftapi.APIInterface = &usersGetMockAPI{t: t}
+	stdout := bytes.NewBufferString("")
+
+	getUserCmd := NewGetUserCmd(&api)
+	getUserCmd.Flags().StringP("output", "o", "", outputFlagUsage)
+	getUserCmd.SetArgs([]string{"spoody", "-o", "template={{.login}} {{.wallet}}"})
+	getUserCmd.SetOut(stdout)
+	err := getUserCmd.Execute()
+	assert.Nil(t, err)
+	assert.Equal(t, "spoody 1337", stdout.String())
+}
@@ -2,67 +2,24 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"goft/pkg/ftapi"
-	"io"
 	"io/ioutil"
-	"net/http"
-	"os"
 	"testing"
 )
 
 type removePointsMockAPI struct {
+	baseMockAPI
 	t *testing.T
 }
-func (m *removePointsMockAPI) Get(url string) (*http.Response, error) {
-	return nil, nil
-}
-func (m *removePointsMockAPI) Post(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *removePointsMockAPI) Delete(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *removePointsMockAPI) PostJSON(url string, data interface{}) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *removePointsMockAPI) Patch(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *removePointsMockAPI) PatchJSON(url string, data interface{}) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *removePointsMockAPI) SetUserImage(login string, img *os.File) error {
-	return nil
-}
-func (m *removePointsMockAPI) CreateUser(user *ftapi.User, campusID int) error {
-	return nil
-}
-func (m *removePointsMockAPI) CreateClose(close *ftapi.Close) error {
-	return nil
-}
-func (m *removePointsMockAPI) GetUserByLogin(login string) (*ftapi.User, error) {
-	return nil, nil
-}
-func (m *removePointsMockAPI) UpdateUser(login string, data *ftapi.User) error  {
-	return nil
-}
-func (m *removePointsMockAPI) AddCorrectionPoints(login string, points uint, reason string) error{
-	return nil
-}
-func (m *removePointsMockAPI) RemoveCorrectionPoints(login string, points uint, reason string) error{
+func (m *removePointsMockAPI) RemoveCorrectionPointsContext(ctx context.Context, login string, points uint, reason string) error{
 	assert.Equal(m.t, "norminet", login)
 	assert.Equal(m.t, uint(2), points)
 	assert.Equal(m.t, "Meow", reason)
 	return nil
 }
-func (m *removePointsMockAPI) GetUserAgus(login string) ([]ftapi.Agu, error) {
-	return nil, nil
-}
-func (m *removePointsMockAPI) CreateFreePastAgu(login string, duration int, reason string) error {
-	return nil
-}
 
 func TestNewRemovePointsCmd(t *testing.T) {
 	var api ftapi.APIInterface = &removePointsMockAPI{t: t}
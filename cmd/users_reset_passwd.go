@@ -1,42 +1,110 @@
 package cmd
 
 import (
-	"errors"
 	"fmt"
 	"github.com/sethvargo/go-password/password"
 	"github.com/spf13/cobra"
 	"goft/pkg/ftapi"
 	"gopkg.in/gomail.v2"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
 )
 
+// deliveryAttempts is the number of times the new password email is sent before giving up
+const deliveryAttempts = 3
+
+// deliveryRetryDelay is waited between two attempts to send the new password email
+var deliveryRetryDelay = 2 * time.Second
+
+// newPasswordMail builds the email sending password to user
+func newPasswordMail(from string, user *ftapi.User, password string) *gomail.Message {
+	m := gomail.NewMessage()
+	m.SetHeader("From", from)
+	m.SetAddressHeader("To", user.Email, user.FirstName)
+	m.SetHeader("Subject", "New password for your 42 Account")
+
+	htmlBody := fmt.Sprintf(`<html>
+Hello <b>%s</b>!<br />
+<br />
+This is your new 42 Intranet password: <b>%s</b><br />
+<br />
+You can use it to login <a href="https://signin.intra.42.fr/users/sign_in">here</a><br />
+<br />
+<b>Do not reply to this email, if you still have a problem use Slack to report it.</b><br />
+</html>
+`, user.FirstName, password)
+	txtBody := fmt.Sprintf(`Hello %s!
+
+This is your new 42 Intranet password: %s
+
+You can use it to login here: https://signin.intra.42.fr/users/sign_in
+
+Do not reply to this email, if you still have a problem use Slack to report it.
+`, user.FirstName, password)
+	m.SetBody("text/html", htmlBody)
+	m.AddAlternative("text/plain", txtBody)
+	return m
+}
+
+// deliverMail sends m through sender, reconnecting with dial between the attempts, sender is closed
+func deliverMail(dial smtpDialer, settings smtpSettings, sender gomail.SendCloser, m *gomail.Message) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = gomail.Send(sender, m)
+		_ = sender.Close()
+		if err == nil || attempt == deliveryAttempts {
+			return err
+		}
+		time.Sleep(deliveryRetryDelay)
+		sender, err = dial(settings)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// saveUndeliveredPassword writes the password of login to a file only readable by the current user and returns its path
+func saveUndeliveredPassword(login string, password string) (string, error) {
+	dir := filepath.Join(profileDir(), "undelivered")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, login+".txt")
+	return path, ioutil.WriteFile(path, []byte(password+"\n"), 0600)
+}
+
 // NewResetPasswdCmd create the update user cmd
-func NewResetPasswdCmd(api *ftapi.APIInterface, p password.PasswordGenerator) *cobra.Command {
+func NewResetPasswdCmd(api *ftapi.APIInterface, p password.PasswordGenerator, dial smtpDialer) *cobra.Command {
 	cmd := cobra.Command{
 		Use:   "reset-passwd login",
 		Short: "Send a reset password email to the user",
-		Long: "This command requires the Advanced tutor role",
+		Long: `This command requires the Advanced tutor role
+
+The SMTP flags default to the smtp section of the config file.
+The connection to the SMTP server is checked before the password is changed.
+If the email still can't be sent afterwards, it is retried, then the new password
+is saved to a file only readable by you so it isn't lost, in the undelivered directory of the profile:
+~/.config/goft/undelivered, or ~/.config/goft/profiles/<name>/undelivered when a profile is selected.`,
+		Annotations: map[string]string{
+			argsAnnotation: "login",
+		},
+		ValidArgsFunction: completeArgs(api),
 		Args: func(cmd *cobra.Command, args []string) error {
 			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
 				return err
 			}
-			smtpUser, _ := cmd.LocalFlags().GetString("smtp-user")
-			smtpPass, _ := cmd.LocalFlags().GetString("smtp-pass")
-			smtpHost, _ := cmd.LocalFlags().GetString("smtp-host")
-			smtpPort, _ := cmd.LocalFlags().GetInt("smtp-port")
-			fromEmail, _ := cmd.LocalFlags().GetString("from-email")
-			if smtpUser == "" || smtpPass == "" || smtpHost == "" || smtpPort <= 0 || fromEmail == "" {
-				return errors.New("missing required flags")
-			}
-			return nil
+			return readSMTPSettings(cmd).validate()
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			smtpUser, _ := cmd.LocalFlags().GetString("smtp-user")
-			smtpPass, _ := cmd.LocalFlags().GetString("smtp-pass")
-			smtpHost, _ := cmd.LocalFlags().GetString("smtp-host")
-			smtpPort, _ := cmd.LocalFlags().GetInt("smtp-port")
-			fromEmail, _ := cmd.LocalFlags().GetString("from-email")
+			settings := readSMTPSettings(cmd)
+			login, err := resolveLogin(cmd.Context(), *api, args[0])
+			if err != nil {
+				return err
+			}
 			// Get User email
-			user, err := (*api).GetUserByLogin(args[0])
+			user, err := (*api).GetUserByLoginContext(cmd.Context(), login)
 			if err != nil {
 				return err
 			}
@@ -45,69 +113,57 @@ func NewResetPasswdCmd(api *ftapi.APIInterface, p password.PasswordGenerator) *c
 			if err != nil {
 				return err
 			}
-			// Update the user's password
+			showPass, _ := cmd.Flags().GetBool("show-pass")
+			out := cmd.OutOrStdout()
 			newUser := ftapi.User{
 				Password: newPass,
 			}
-			err = (*api).UpdateUser(user.Login, &newUser)
+			if isDryRun(cmd) {
+				err = (*api).UpdateUserContext(cmd.Context(), user.Login, &newUser)
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintf(out, "Would send the new password to %s through %s:%d\n", user.Email, settings.Host, settings.Port)
+				if showPass {
+					_, _ = fmt.Fprintf(out, "New password is: %s\n", newPass)
+				}
+				return nil
+			}
+			// The password can't be changed back, so the SMTP server must be reachable before changing it
+			sender, err := dial(settings)
 			if err != nil {
-				return err
+				return fmt.Errorf("the password was not changed, failed connecting to the SMTP server: %w", err)
 			}
-			// Send the password via email
-			mailDialer := gomail.NewDialer(smtpHost, smtpPort, smtpUser, smtpPass)
-			m := gomail.NewMessage()
-			m.SetHeader("From", fromEmail)
-			m.SetAddressHeader("To", user.Email, user.FirstName)
-			m.SetHeader("Subject", "New password for your 42 Account")
-
-			htmlBody := fmt.Sprintf(`<html>
-Hello <b>%s</b>!<br />
-<br />
-This is your new 42 Intranet password: <b>%s</b><br />
-<br />
-You can use it to login <a href="https://signin.intra.42.fr/users/sign_in">here</a><br />
-<br />
-<b>Do not reply to this email, if you still have a problem use Slack to report it.</b><br />
-</html>
-`, user.FirstName, newPass)
-			txtBody := fmt.Sprintf(`Hello %s!
-
-This is your new 42 Intranet password: %s
-
-You can use it to login here: https://signin.intra.42.fr/users/sign_in
-
-Do not reply to this email, if you still have a problem use Slack to report it.
-`, user.FirstName, newPass)
-			m.SetBody("text/html", htmlBody)
-			m.AddAlternative("text/plain", txtBody)
-
-			err = mailDialer.DialAndSend(m)
+			// Update the user's password
+			err = (*api).UpdateUserContext(cmd.Context(), user.Login, &newUser)
 			if err != nil {
+				_ = sender.Close()
 				return err
 			}
-			showPass, _ := cmd.Flags().GetBool("show-pass")
+			// Send the password via email
+			err = deliverMail(dial, settings, sender, newPasswordMail(settings.From, user, newPass))
 			if showPass {
-				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "New password is: %s\n", newPass)
+				_, _ = fmt.Fprintf(out, "New password is: %s\n", newPass)
+			}
+			if err != nil {
+				path, saveErr := saveUndeliveredPassword(user.Login, newPass)
+				if saveErr != nil {
+					// Printing it is the last way not to lose it
+					return fmt.Errorf("the password of %s was changed but the email could not be sent: %s, and saving it failed: %w\nThe new password is: %s", user.Login, err, saveErr, newPass)
+				}
+				return fmt.Errorf("the password of %s was changed but the email could not be sent: %w\nThe new password was saved to %s, give it to %s and delete the file", user.Login, err, path, user.Login)
 			}
+			_, _ = fmt.Fprintf(out, "The new password of %s was sent to %s\n", user.Login, user.Email)
 			return nil
 		},
 	}
-	cmd.Flags().String("from-email", "", "Address to be used as a sender")
-	cmd.Flags().String("smtp-user", "", "SMTP username")
-	cmd.Flags().String("smtp-pass", "", "SMTP password")
-	cmd.Flags().String("smtp-host", "", "SMTP host")
-	cmd.Flags().Int("smtp-port", 25, "SMTP port")
+	addSMTPFlags(&cmd)
 	cmd.Flags().Bool("show-pass", false, "Print the generated password")
-	_ = cmd.MarkFlagRequired("from-email")
-	_ = cmd.MarkFlagRequired("smtp-user")
-	_ = cmd.MarkFlagRequired("smtp-pass")
-	_ = cmd.MarkFlagRequired("smtp-host")
-	_ = cmd.MarkFlagRequired("smtp-port")
 	return &cmd
 }
 
 var passwdGenerator, _ = password.NewGenerator(nil)
-var resetPasswdCmd = NewResetPasswdCmd(&API, passwdGenerator)
+var resetPasswdCmd = NewResetPasswdCmd(&API, passwdGenerator, dialSMTP)
 
 func init() {
 	usersCmd.AddCommand(resetPasswdCmd)
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"goft/pkg/ftapi"
+	"gopkg.in/gomail.v2"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sethvargo/go-password/password"
+	"github.com/stretchr/testify/assert"
+)
+
+type resetPasswdMockAPI struct {
+	baseMockAPI
+	updated []string
+}
+
+func (m *resetPasswdMockAPI) GetUserByLoginContext(ctx context.Context, login string) (*ftapi.User, error) {
+	return &ftapi.User{Login: login, Email: login + "@1337.ma", FirstName: "Mehdi"}, nil
+}
+
+func (m *resetPasswdMockAPI) UpdateUserContext(ctx context.Context, login string, data *ftapi.User) error {
+	m.updated = append(m.updated, data.Password)
+	return nil
+}
+
+// mockSMTP fails the first failures sends, or every dial when dialErr is set
+type mockSMTP struct {
+	dialErr  error
+	failures int
+	sent     int
+}
+
+func (m *mockSMTP) dial(settings smtpSettings) (gomail.SendCloser, error) {
+	if m.dialErr != nil {
+		return nil, m.dialErr
+	}
+	return m, nil
+}
+
+func (m *mockSMTP) Send(from string, to []string, msg io.WriterTo) error {
+	if m.failures > 0 {
+		m.failures--
+		return errors.New("454 try again later")
+	}
+	m.sent++
+	return nil
+}
+
+func (m *mockSMTP) Close() error {
+	return nil
+}
+
+func executeResetPasswd(api ftapi.APIInterface, smtp *mockSMTP) (string, error) {
+	stdout := bytes.NewBufferString("")
+	resetCmd := NewResetPasswdCmd(&api, password.NewMockGenerator("s3cr3tpassw0rd", nil), smtp.dial)
+	resetCmd.SetArgs([]string{"spoody", "--smtp-host", "smtp.test", "--smtp-user", "goft", "--smtp-pass", "pass", "--from-email", "noreply@1337.ma"})
+	resetCmd.SetOut(stdout)
+	resetCmd.SetErr(bytes.NewBufferString(""))
+	err := resetCmd.Execute()
+	return stdout.String(), err
+}
+
+func TestResetPasswdSMTPUnreachable(t *testing.T) {
+	api := &resetPasswdMockAPI{}
+	_, err := executeResetPasswd(api, &mockSMTP{dialErr: errors.New("connection refused")})
+	assert.EqualError(t, err, "the password was not changed, failed connecting to the SMTP server: connection refused")
+	assert.Empty(t, api.updated)
+}
+
+func TestResetPasswdRetry(t *testing.T) {
+	defer func(delay time.Duration) { deliveryRetryDelay = delay }(deliveryRetryDelay)
+	deliveryRetryDelay = 0
+	api := &resetPasswdMockAPI{}
+	smtp := &mockSMTP{failures: 2}
+	stdout, err := executeResetPasswd(api, smtp)
+	assert.Nil(t, err)
+	assert.Equal(t, "The new password of spoody was sent to spoody@1337.ma\n", stdout)
+	assert.Equal(t, []string{"s3cr3tpassw0rd"}, api.updated)
+	assert.Equal(t, 1, smtp.sent)
+}
+
+func TestResetPasswdUndelivered(t *testing.T) {
+	defer func(delay time.Duration) { deliveryRetryDelay = delay }(deliveryRetryDelay)
+	deliveryRetryDelay = 0
+	home := os.Getenv("HOME")
+	defer os.Setenv("HOME", home)
+	assert.Nil(t, os.Setenv("HOME", t.TempDir()))
+
+	api := &resetPasswdMockAPI{}
+	_, err := executeResetPasswd(api, &mockSMTP{failures: deliveryAttempts})
+	path := filepath.Join(profileDir(), "undelivered", "spoody.txt")
+	assert.EqualError(t, err, "the password of spoody was changed but the email could not be sent: gomail: could not send email 1: 454 try again later\nThe new password was saved to "+path+", give it to spoody and delete the file")
+	saved, readErr := ioutil.ReadFile(path)
+	assert.Nil(t, readErr)
+	assert.Equal(t, "s3cr3tpassw0rd\n", string(saved))
+	info, _ := os.Stat(path)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestSaveUndeliveredPasswordInProfile(t *testing.T) {
+	home := os.Getenv("HOME")
+	defer os.Setenv("HOME", home)
+	assert.Nil(t, os.Setenv("HOME", t.TempDir()))
+	defer func(current string) { profile = current }(profile)
+	profile = "benguerir"
+
+	path, err := saveUndeliveredPassword("spoody", "s3cr3tpassw0rd")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(goftDir(), "profiles", "benguerir", "undelivered", "spoody.txt"), path)
+}
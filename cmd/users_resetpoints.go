@@ -1,19 +1,71 @@
 package cmd
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"goft/pkg/ftapi"
 	"strconv"
 
 	"github.com/spf13/cobra"
 )
 
+// resetPointsSteps brings the points of login from current to zero then adds points,
+// if adding fails the current points are restored
+func resetPointsSteps(api ftapi.APIInterface, login string, current int, points uint, reason string) []step {
+	var steps []step
+	// If greater than zero, delete all points
+	if current > 0 {
+		steps = append(steps, step{
+			Name: fmt.Sprintf("remove %d correction points from %s", current, login),
+			Do: func(ctx context.Context) error {
+				return api.RemoveCorrectionPointsContext(ctx, login, uint(current), reason)
+			},
+			Undo: func(ctx context.Context) error {
+				return api.AddCorrectionPointsContext(ctx, login, uint(current), reason)
+			},
+		})
+	}
+	// If less than zero reset it to zero
+	if current < 0 {
+		steps = append(steps, step{
+			Name: fmt.Sprintf("add %d correction points to %s", -current, login),
+			Do: func(ctx context.Context) error {
+				return api.AddCorrectionPointsContext(ctx, login, uint(-current), reason)
+			},
+			Undo: func(ctx context.Context) error {
+				return api.RemoveCorrectionPointsContext(ctx, login, uint(-current), reason)
+			},
+		})
+	}
+	// Add required points
+	if points > 0 {
+		steps = append(steps, step{
+			Name: fmt.Sprintf("add %d correction points to %s", points, login),
+			Do: func(ctx context.Context) error {
+				return api.AddCorrectionPointsContext(ctx, login, points, reason)
+			},
+			Undo: func(ctx context.Context) error {
+				return api.RemoveCorrectionPointsContext(ctx, login, points, reason)
+			},
+		})
+	}
+	return steps
+}
+
 // NewResetPointsCmd create reset points command
 func NewResetPointsCmd(api *ftapi.APIInterface) *cobra.Command {
 	return &cobra.Command{
 		Use:   "reset-points login points reason",
 		Short: "Reset correction points for a user",
-		Long: "This command requires the Advanced tutor role",
+		Long: `This command requires the Advanced tutor role
+
+The points are removed, then the new points are added. If adding them fails,
+the removed points are given back so the user is not left at zero.`,
+		Annotations: map[string]string{
+			argsAnnotation: "login",
+		},
+		ValidArgsFunction: completeArgs(api),
 		Args: func(cmd *cobra.Command, args []string) error {
 			if err := cobra.ExactArgs(3)(cmd, args); err != nil {
 				return err
@@ -25,31 +77,22 @@ func NewResetPointsCmd(api *ftapi.APIInterface) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			// Get current user points
-			user, err := (*api).GetUserByLogin(args[0])
+			login, err := resolveLogin(cmd.Context(), *api, args[0])
 			if err != nil {
 				return err
 			}
-			// If greater than zero, delete all points
-			if user.CorrectionPoints > 0 {
-				err = (*api).RemoveCorrectionPoints(user.Login, uint(user.CorrectionPoints), args[2])
-				if err != nil {
-					return err
-				}
-			}
-			// If less than zero reset it to zero
-			if user.CorrectionPoints < 0 {
-				err = (*api).AddCorrectionPoints(user.Login, uint(user.CorrectionPoints * -1), args[2])
-				if err != nil {
-					return err
-				}
+			// Get current user points
+			user, err := (*api).GetUserByLoginContext(cmd.Context(), login)
+			if err != nil {
+				return err
 			}
-			// Add required points
 			points, _ := strconv.ParseUint(args[1], 10, 0)
-			if points == 0 {
-				return nil
+			err = runSteps(cmd.Context(), cmd.OutOrStdout(), resetPointsSteps(*api, user.Login, user.CorrectionPoints, uint(points), args[2]))
+			// The points are read again to report the final state, even after a failure
+			if final, getErr := (*api).GetUserByLoginContext(cmd.Context(), user.Login); getErr == nil && !isDryRun(cmd) {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s has %d correction points\n", final.Login, final.CorrectionPoints)
 			}
-			return (*api).AddCorrectionPoints(user.Login, uint(points), args[2])
+			return err
 		},
 	}
 }
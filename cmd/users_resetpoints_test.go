@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"goft/pkg/ftapi"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// resetPointsMockAPI keeps the points of a single user, adding failAdd points fails
+type resetPointsMockAPI struct {
+	baseMockAPI
+	points  int
+	failAdd uint
+}
+
+func (m *resetPointsMockAPI) GetUserByLoginContext(ctx context.Context, login string) (*ftapi.User, error) {
+	return &ftapi.User{Login: login, CorrectionPoints: m.points}, nil
+}
+
+func (m *resetPointsMockAPI) AddCorrectionPointsContext(ctx context.Context, login string, points uint, reason string) error {
+	if points == m.failAdd {
+		return errors.New("422 Unprocessable Entity")
+	}
+	m.points += int(points)
+	return nil
+}
+
+func (m *resetPointsMockAPI) RemoveCorrectionPointsContext(ctx context.Context, login string, points uint, reason string) error {
+	m.points -= int(points)
+	return nil
+}
+
+func executeResetPoints(api ftapi.APIInterface, points string) (string, error) {
+	stdout := bytes.NewBufferString("")
+	resetCmd := NewResetPointsCmd(&api)
+	resetCmd.SilenceUsage = true
+	resetCmd.SetArgs([]string{"spoody", points, "Testing purposes"})
+	resetCmd.SetOut(stdout)
+	resetCmd.SetErr(bytes.NewBufferString(""))
+	err := resetCmd.Execute()
+	return stdout.String(), err
+}
+
+func TestResetPoints(t *testing.T) {
+	api := &resetPointsMockAPI{points: 7}
+	stdout, err := executeResetPoints(api, "5")
+	assert.Nil(t, err)
+	assert.Equal(t, 5, api.points)
+	assert.Equal(t, `Done: remove 7 correction points from spoody
+Done: add 5 correction points to spoody
+spoody has 5 correction points
+`, stdout)
+}
+
+func TestResetPointsCompensation(t *testing.T) {
+	api := &resetPointsMockAPI{points: 7, failAdd: 5}
+	stdout, err := executeResetPoints(api, "5")
+	assert.EqualError(t, err, "failed to add 5 correction points to spoody, the previous steps were undone: 422 Unprocessable Entity")
+	assert.Equal(t, 7, api.points)
+	assert.Equal(t, `Done: remove 7 correction points from spoody
+Failed: add 5 correction points to spoody: 422 Unprocessable Entity
+Undone: remove 7 correction points from spoody
+spoody has 7 correction points
+`, stdout)
+}
+
+func TestResetNegativePoints(t *testing.T) {
+	api := &resetPointsMockAPI{points: -3}
+	_, err := executeResetPoints(api, "0")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, api.points)
+}
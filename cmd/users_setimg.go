@@ -7,6 +7,21 @@ import (
 	"os"
 )
 
+// validateImage checks that the image file at path exists and its size is accepted by the intra
+func validateImage(path string) error {
+	const minImgLen = 3072
+	const maxImgLen = 1048576
+	// Check if file exists
+	imgFile, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if imgFile.Size() < minImgLen || imgFile.Size() > maxImgLen {
+		return errors.New("image file size is invalid")
+	}
+	return nil
+}
+
 // NewSetImgCmd create the users setimg cmd
 func NewSetImgCmd(api *ftapi.APIInterface) *cobra.Command {
 	cmd := cobra.Command{
@@ -15,29 +30,27 @@ func NewSetImgCmd(api *ftapi.APIInterface) *cobra.Command {
 		Long: `This command requires the Advanced tutor role.
 Image file must be 3Kb and 1Mb
 `,
+		Annotations: map[string]string{
+			argsAnnotation: "login",
+		},
+		ValidArgsFunction: completeArgs(api),
 		Args: func(cmd *cobra.Command, args []string) error {
 			if err := cobra.ExactArgs(2)(cmd, args); err != nil {
 				return err
 			}
-			const minImgLen = 3072
-			const maxImgLen = 1048576
-			// Check if file exists
-			imgFile, err := os.Stat(args[1])
+			return validateImage(args[1])
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			login, err := resolveLogin(cmd.Context(), *api, args[0])
 			if err != nil {
 				return err
 			}
-			if imgFile.Size() < minImgLen || imgFile.Size() > maxImgLen {
-				return errors.New("image file size is invalid")
-			}
-			return nil
-		},
-		RunE: func(cmd *cobra.Command, args []string) error {
 			imgFile, err := os.Open(args[1])
 			if err != nil {
 				return err
 			}
 			defer imgFile.Close()
-			err = (*api).SetUserImage(args[0], imgFile)
+			err = (*api).SetUserImageContext(cmd.Context(), login, imgFile)
 			if err != nil {
 				return err
 			}
@@ -2,38 +2,20 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"goft/pkg/ftapi"
-	"io"
 	"io/ioutil"
-	"net/http"
 	"os"
 	"testing"
 )
 
 type setImgMockAPI struct {
+	baseMockAPI
 	t *testing.T
 }
-func (m *setImgMockAPI) Get(url string) (*http.Response, error) {
-	return nil, nil
-}
-func (m *setImgMockAPI) Delete(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *setImgMockAPI) Post(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *setImgMockAPI) PostJSON(url string, data interface{}) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *setImgMockAPI) Patch(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *setImgMockAPI) PatchJSON(url string, data interface{}) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *setImgMockAPI) SetUserImage(login string, img *os.File) error {
+func (m *setImgMockAPI) SetUserImageContext(ctx context.Context, login string, img *os.File) error {
 	assert.Equal(m.t, "spoody", login)
 	assert.Equal(m.t, "../tests/profile_photo.png", img.Name())
 	stat, err := img.Stat()
@@ -43,30 +25,6 @@ func (m *setImgMockAPI) SetUserImage(login string, img *os.File) error {
 	assert.Equal(m.t, int64(99412), stat.Size())
 	return nil
 }
-func (m *setImgMockAPI) CreateUser(user *ftapi.User, campusID int) error {
-	return nil
-}
-func (m *setImgMockAPI) CreateClose(close *ftapi.Close) error {
-	return nil
-}
-func (m *setImgMockAPI) GetUserByLogin(login string) (*ftapi.User, error) {
-	return nil, nil
-}
-func (m *setImgMockAPI) UpdateUser(login string, data *ftapi.User) error  {
-	return nil
-}
-func (m *setImgMockAPI) AddCorrectionPoints(login string, points uint, reason string) error{
-	return nil
-}
-func (m *setImgMockAPI) RemoveCorrectionPoints(login string, points uint, reason string) error{
-	return nil
-}
-func (m *setImgMockAPI) GetUserAgus(login string) ([]ftapi.Agu, error) {
-	return nil, nil
-}
-func (m *setImgMockAPI) CreateFreePastAgu(login string, duration int, reason string) error {
-	return nil
-}
 
 func TestNewSetImgCmd(t *testing.T) {
 	var api ftapi.APIInterface = &setImgMockAPI{t: t}
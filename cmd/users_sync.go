@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goft/pkg/ftapi"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// syncChange is a field of a user that differs from the roster
+type syncChange struct {
+	Field string
+	From  string
+	To    string
+}
+
+// syncAction is what must be done for a roster entry to match the intra,
+// an action without Create nor Changes means the user is up to date
+type syncAction struct {
+	Entry rosterEntry
+	// Login of the existing user, empty when it must be created
+	Login   string
+	Create  bool
+	Changes []syncChange
+	// Conflict explains why the entry can't be synced, like its email belonging to another login
+	Conflict string
+}
+
+// planSync compares every entry to the intra user with the same login, then with the same email like users create --from.
+// An entry whose email belongs to another login is a conflict, creating it would fail
+func planSync(ctx context.Context, api ftapi.APIInterface, entries []rosterEntry) ([]syncAction, error) {
+	actions := make([]syncAction, 0, len(entries))
+	for i := range entries {
+		entry := entries[i]
+		user, err := findExistingUser(ctx, api, &entry)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", entry.Line, err)
+		}
+		if user == nil {
+			actions = append(actions, syncAction{Entry: entry, Create: true})
+			continue
+		}
+		action := syncAction{Entry: entry, Login: user.Login}
+		if entry.Login != "" && user.Login != entry.Login {
+			action.Conflict = fmt.Sprintf("email %s already belongs to %s", entry.Email, user.Login)
+			actions = append(actions, action)
+			continue
+		}
+		for _, field := range []syncChange{
+			{"email", user.Email, entry.Email},
+			{"first_name", user.FirstName, entry.FirstName},
+			{"last_name", user.LastName, entry.LastName},
+			{"kind", user.Kind, entry.Kind},
+		} {
+			if field.From != field.To {
+				action.Changes = append(action.Changes, field)
+			}
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// countSyncActions returns the number of users to create, update, left unchanged and in conflict
+func countSyncActions(actions []syncAction) (creates int, updates int, unchanged int, conflicts int) {
+	for _, action := range actions {
+		switch {
+		case action.Conflict != "":
+			conflicts++
+		case action.Create:
+			creates++
+		case len(action.Changes) > 0:
+			updates++
+		default:
+			unchanged++
+		}
+	}
+	return
+}
+
+// printSyncPlan prints the creates and updates of the plan, like terraform plan
+func printSyncPlan(w io.Writer, actions []syncAction) {
+	for _, action := range actions {
+		entry := action.Entry
+		switch {
+		case action.Conflict != "":
+			_, _ = fmt.Fprintf(w, "! conflict %s: %s\n", entry.Login, action.Conflict)
+		case action.Create:
+			name := entry.Login
+			if name == "" {
+				name = entry.Email
+			}
+			_, _ = fmt.Fprintf(w, "+ create %s\n", name)
+			_, _ = fmt.Fprintf(w, "    email:      %q\n", entry.Email)
+			_, _ = fmt.Fprintf(w, "    first_name: %q\n", entry.FirstName)
+			_, _ = fmt.Fprintf(w, "    last_name:  %q\n", entry.LastName)
+			_, _ = fmt.Fprintf(w, "    kind:       %q\n", entry.Kind)
+			_, _ = fmt.Fprintf(w, "    campus_id:  %d\n", entry.CampusID)
+		case len(action.Changes) > 0:
+			_, _ = fmt.Fprintf(w, "~ update %s\n", action.Login)
+			for _, change := range action.Changes {
+				_, _ = fmt.Fprintf(w, "    %-11s %q -> %q\n", change.Field+":", change.From, change.To)
+			}
+		}
+	}
+	creates, updates, unchanged, conflicts := countSyncActions(actions)
+	_, _ = fmt.Fprintf(w, "\nPlan: %d to create, %d to update, %d unchanged", creates, updates, unchanged)
+	if conflicts > 0 {
+		_, _ = fmt.Fprintf(w, ", %d in conflict", conflicts)
+	}
+	_, _ = fmt.Fprintln(w, ".")
+}
+
+// applySync creates and updates the users of the plan, it goes on after a failure and returns the number of failures.
+// The conflicts are left unchanged
+func applySync(ctx context.Context, api ftapi.APIInterface, w io.Writer, actions []syncAction) int {
+	failures := 0
+	for _, action := range actions {
+		entry := action.Entry
+		switch {
+		case action.Conflict != "":
+			continue
+		case action.Create:
+			user := ftapi.User{
+				Login:     entry.Login,
+				Email:     entry.Email,
+				FirstName: entry.FirstName,
+				LastName:  entry.LastName,
+				Kind:      entry.Kind,
+			}
+			err := api.CreateUserContext(ctx, &user, entry.CampusID)
+			if err != nil {
+				failures++
+				_, _ = fmt.Fprintf(w, "line %d: failed creating %s: %s\n", entry.Line, entry.Email, err)
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "Created %s (%d)\n", user.Login, user.ID)
+		case len(action.Changes) > 0:
+			// UpdateUser only sends the fields that are set
+			data := ftapi.User{}
+			fields := make([]string, 0, len(action.Changes))
+			for _, change := range action.Changes {
+				switch change.Field {
+				case "email":
+					data.Email = change.To
+				case "first_name":
+					data.FirstName = change.To
+				case "last_name":
+					data.LastName = change.To
+				case "kind":
+					data.Kind = change.To
+				}
+				fields = append(fields, change.Field)
+			}
+			err := api.UpdateUserContext(ctx, action.Login, &data)
+			if err != nil {
+				failures++
+				_, _ = fmt.Fprintf(w, "line %d: failed updating %s: %s\n", entry.Line, action.Login, err)
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "Updated %s: %s\n", action.Login, strings.Join(fields, ", "))
+		}
+	}
+	return failures
+}
+
+// NewUsersSyncCmd creates the users sync cmd
+func NewUsersSyncCmd(api *ftapi.APIInterface) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync roster",
+		Short: "Make the intra users match a roster",
+		Long: `This command requires the Advanced tutor role
+
+The roster is a csv or json file in the same format as users create --from.
+Each user is looked up by login, then by email, and a plan is printed:
+missing users are created and the email, first_name, last_name and kind of the others are updated.
+A user whose email belongs to another login is a conflict, it is left unchanged and fails the command.
+The plan is only applied after confirmation, or with --yes.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := readRoster(args[0])
+			if err != nil {
+				return err
+			}
+			if validateRosterEntries(entries, viper.GetInt("campus_id")) > 0 {
+				for _, entry := range entries {
+					if entry.Err != nil {
+						cmd.PrintErrf("line %d: %s\n", entry.Line, entry.Err)
+					}
+				}
+				return errors.New("the roster is invalid, nothing was changed")
+			}
+			actions, err := planSync(cmd.Context(), *api, entries)
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			printSyncPlan(out, actions)
+			creates, updates, _, conflicts := countSyncActions(actions)
+			var errConflicts error
+			if conflicts > 0 {
+				errConflicts = fmt.Errorf("%d users are in conflict and must be fixed by hand", conflicts)
+			}
+			if creates+updates == 0 {
+				return errConflicts
+			}
+			yes, _ := cmd.Flags().GetBool("yes")
+			// Nothing is written in dry run mode, the requests are only printed
+			if !yes && !isDryRun(cmd) && !confirm(cmd, "\nApply these changes?") {
+				_, _ = fmt.Fprintln(out, "Nothing was changed")
+				return nil
+			}
+			failures := applySync(cmd.Context(), *api, out, actions)
+			if failures > 0 {
+				return fmt.Errorf("%d of %d changes failed", failures, creates+updates)
+			}
+			return errConflicts
+		},
+	}
+	cmd.Flags().BoolP("yes", "y", false, "Apply the plan without asking for confirmation")
+	return cmd
+}
+
+var usersSyncCmd = NewUsersSyncCmd(&API)
+
+func init() {
+	usersCmd.AddCommand(usersSyncCmd)
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"goft/pkg/ftapi"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type usersSyncMockAPI struct {
+	baseMockAPI
+	created []string
+	updated map[string]ftapi.User
+}
+
+func (m *usersSyncMockAPI) GetUserByLoginContext(ctx context.Context, login string) (*ftapi.User, error) {
+	switch login {
+	case "spoody":
+		return &ftapi.User{Login: "spoody", Email: "old@1337.ma", FirstName: "Mehdi", LastName: "Bounya", Kind: "student"}, nil
+	case "uptodate":
+		return &ftapi.User{Login: "uptodate", Email: "uptodate@1337.ma", FirstName: "Up", LastName: "ToDate", Kind: "student"}, nil
+	}
+	return nil, &ftapi.APIError{StatusCode: 404}
+}
+
+func (m *usersSyncMockAPI) GetUserByEmailContext(ctx context.Context, email string) (*ftapi.User, error) {
+	if email == "taken@1337.ma" {
+		return &ftapi.User{Login: "taken", Email: "taken@1337.ma", FirstName: "Taken", LastName: "User", Kind: "student"}, nil
+	}
+	return nil, ftapi.ErrNotFound
+}
+
+func (m *usersSyncMockAPI) CreateUserContext(ctx context.Context, user *ftapi.User, campusID int) error {
+	m.created = append(m.created, user.Email)
+	user.ID = 42
+	user.Login = "newbie"
+	return nil
+}
+
+func (m *usersSyncMockAPI) UpdateUserContext(ctx context.Context, login string, data *ftapi.User) error {
+	m.updated[login] = *data
+	return nil
+}
+
+func writeSyncRoster(t *testing.T) string {
+	path := filepath.Join(t.TempDir(), "roster.csv")
+	err := ioutil.WriteFile(path, []byte(`email,first_name,last_name,kind,campus_id,login
+new@1337.ma,New,User,student,21,
+mehdi@1337.ma,Mehdi,Bounya,admin,21,spoody
+uptodate@1337.ma,Up,ToDate,student,21,uptodate
+`), 0600)
+	assert.Nil(t, err)
+	return path
+}
+
+const expectedSyncPlan = `+ create new@1337.ma
+    email:      "new@1337.ma"
+    first_name: "New"
+    last_name:  "User"
+    kind:       "student"
+    campus_id:  21
+~ update spoody
+    email:      "old@1337.ma" -> "mehdi@1337.ma"
+    kind:       "student" -> "admin"
+
+Plan: 1 to create, 1 to update, 1 unchanged.
+`
+
+func TestUsersSyncInvalidRoster(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "roster.csv")
+	assert.Nil(t, ioutil.WriteFile(path, []byte("email,first_name,last_name,kind,campus_id\nnew@1337.ma,New,User,staff,21\n"), 0600))
+	var api ftapi.APIInterface = &usersSyncMockAPI{}
+	stderr := bytes.NewBufferString("")
+	syncCmd := NewUsersSyncCmd(&api)
+	syncCmd.SetArgs([]string{path})
+	syncCmd.SetOut(bytes.NewBufferString(""))
+	syncCmd.SetErr(stderr)
+	err := syncCmd.Execute()
+	assert.EqualError(t, err, "the roster is invalid, nothing was changed")
+	assert.Contains(t, stderr.String(), "line 2: kind must be admin, student or external\n")
+}
+
+func TestUsersSyncNotConfirmed(t *testing.T) {
+	api := &usersSyncMockAPI{updated: map[string]ftapi.User{}}
+	var apiInterface ftapi.APIInterface = api
+	stdout := bytes.NewBufferString("")
+	syncCmd := NewUsersSyncCmd(&apiInterface)
+	syncCmd.SetArgs([]string{writeSyncRoster(t)})
+	syncCmd.SetOut(stdout)
+	syncCmd.SetIn(bytes.NewBufferString("n\n"))
+	assert.Nil(t, syncCmd.Execute())
+	assert.Equal(t, expectedSyncPlan+"\nApply these changes? [y/N] Nothing was changed\n", stdout.String())
+	assert.Empty(t, api.created)
+	assert.Empty(t, api.updated)
+}
+
+func TestUsersSyncApply(t *testing.T) {
+	api := &usersSyncMockAPI{updated: map[string]ftapi.User{}}
+	var apiInterface ftapi.APIInterface = api
+	stdout := bytes.NewBufferString("")
+	syncCmd := NewUsersSyncCmd(&apiInterface)
+	syncCmd.SetArgs([]string{writeSyncRoster(t)})
+	syncCmd.SetOut(stdout)
+	syncCmd.SetIn(bytes.NewBufferString("yes\n"))
+	assert.Nil(t, syncCmd.Execute())
+	assert.Equal(t, expectedSyncPlan+"\nApply these changes? [y/N] Created newbie (42)\nUpdated spoody: email, kind\n", stdout.String())
+	assert.Equal(t, []string{"new@1337.ma"}, api.created)
+	assert.Equal(t, map[string]ftapi.User{"spoody": {Email: "mehdi@1337.ma", Kind: "admin"}}, api.updated)
+}
+
+func TestUsersSyncConflict(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "roster.csv")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(`email,first_name,last_name,kind,campus_id,login
+taken@1337.ma,Taken,User,student,21,renamed
+mehdi@1337.ma,Mehdi,Bounya,admin,21,spoody
+`), 0600))
+	api := &usersSyncMockAPI{updated: map[string]ftapi.User{}}
+	var apiInterface ftapi.APIInterface = api
+	stdout := bytes.NewBufferString("")
+	syncCmd := NewUsersSyncCmd(&apiInterface)
+	syncCmd.SetArgs([]string{path, "--yes"})
+	syncCmd.SetOut(stdout)
+	syncCmd.SilenceUsage = true
+	err := syncCmd.Execute()
+	assert.EqualError(t, err, "1 users are in conflict and must be fixed by hand")
+	assert.Equal(t, `! conflict renamed: email taken@1337.ma already belongs to taken
+~ update spoody
+    email:      "old@1337.ma" -> "mehdi@1337.ma"
+    kind:       "student" -> "admin"
+
+Plan: 0 to create, 1 to update, 0 unchanged, 1 in conflict.
+Updated spoody: email, kind
+`, stdout.String())
+	assert.Empty(t, api.created)
+	assert.Equal(t, map[string]ftapi.User{"spoody": {Email: "mehdi@1337.ma", Kind: "admin"}}, api.updated)
+}
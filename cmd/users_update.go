@@ -15,13 +15,17 @@ func NewUpdateUserCmd(api *ftapi.APIInterface) *cobra.Command {
 		Use:   "update login",
 		Short: "Update a user's data",
 		Long: "This command requires the Advanced tutor role",
+		Annotations: map[string]string{
+			argsAnnotation: "login",
+		},
+		ValidArgsFunction: completeArgs(api),
 		Args: func(cmd *cobra.Command, args []string) error {
 			if err := cobra.ExactArgs(1)(cmd, args); err != nil {
 				return err
 			}
 			kind, _ := cmd.LocalFlags().GetString("kind")
 			if kind != "" {
-				if kind != "admin" && kind != "student" && kind != "external" {
+				if !isValidUserKind(kind) {
 					return errors.New("kind must be admin, student or external")
 				}
 			}
@@ -29,6 +33,10 @@ func NewUpdateUserCmd(api *ftapi.APIInterface) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			login, err := resolveLogin(cmd.Context(), *api, args[0])
+			if err != nil {
+				return err
+			}
 			password := ""
 			promptPasswd, _ := cmd.LocalFlags().GetBool("password")
 			if promptPasswd {
@@ -51,7 +59,7 @@ func NewUpdateUserCmd(api *ftapi.APIInterface) *cobra.Command {
 				Kind:           kind,
 				Password:       password,
 			}
-			err := (*api).UpdateUser(args[0], &user)
+			err = (*api).UpdateUserContext(cmd.Context(), login, &user)
 			if err != nil {
 				return err
 			}
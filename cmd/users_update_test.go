@@ -2,51 +2,20 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"goft/pkg/ftapi"
-	"io"
 	"io/ioutil"
-	"net/http"
-	"os"
 	"strings"
 	"testing"
 )
 
 type updateUserMockAPI struct {
+	baseMockAPI
 	t *testing.T
 }
-func (m *updateUserMockAPI) Get(url string) (*http.Response, error) {
-	return nil, nil
-}
-func (m *updateUserMockAPI) Post(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *updateUserMockAPI) Delete(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *updateUserMockAPI) PostJSON(url string, data interface{}) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *updateUserMockAPI) Patch(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *updateUserMockAPI) PatchJSON(url string, data interface{}) (resp *http.Response, err error) {
-	return nil, nil
-}
-func (m *updateUserMockAPI) SetUserImage(login string, img *os.File) error {
-	return nil
-}
-func (m *updateUserMockAPI) CreateUser(user *ftapi.User, campusID int) error {
-	return nil
-}
-func (m *updateUserMockAPI) CreateClose(close *ftapi.Close) error {
-	return nil
-}
-func (m *updateUserMockAPI) GetUserByLogin(login string) (*ftapi.User, error) {
-	return nil, nil
-}
-func (m *updateUserMockAPI) UpdateUser(login string, data *ftapi.User) error  {
+func (m *updateUserMockAPI) UpdateUserContext(ctx context.Context, login string, data *ftapi.User) error  {
 	assert.Equal(m.t, "spoody", login)
 	assert.Equal(m.t, "Spooder", data.FirstName)
 	assert.Equal(m.t, "Webz", data.LastName)
@@ -54,18 +23,6 @@ func (m *updateUserMockAPI) UpdateUser(login string, data *ftapi.User) error  {
 	assert.Equal(m.t, "new_password", data.Password)
 	return nil
 }
-func (m *updateUserMockAPI) AddCorrectionPoints(login string, points uint, reason string) error{
-	return nil
-}
-func (m *updateUserMockAPI) RemoveCorrectionPoints(login string, points uint, reason string) error{
-	return nil
-}
-func (m *updateUserMockAPI) GetUserAgus(login string) ([]ftapi.Agu, error) {
-	return nil, nil
-}
-func (m *updateUserMockAPI) CreateFreePastAgu(login string, duration int, reason string) error {
-	return nil
-}
 
 func TestNewUpdateUserCmd(t *testing.T) {
 	var api ftapi.APIInterface = &updateUserMockAPI{t: t}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"goft/pkg/ftapi"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// resolveLogin replaces the login "me" with the login of the logged in user
+func resolveLogin(ctx context.Context, api ftapi.APIInterface, login string) (string, error) {
+	if login != "me" {
+		return login, nil
+	}
+	user, err := api.GetMeContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	if user == nil || user.Login == "" {
+		return "", errors.New("failed getting the logged in user")
+	}
+	return user.Login, nil
+}
+
+// NewWhoamiCmd create the whoami cmd
+func NewWhoamiCmd(api *ftapi.APIInterface) *cobra.Command {
+	return &cobra.Command{
+		Use:   "whoami",
+		Short: "Show who goft is authenticated as",
+		Long: `Show the logged in user's login, roles and campus, and the scopes of the token.
+
+Without goft auth login, or with --token app, the application's token is described.`,
+		Annotations: map[string]string{
+			tokenAnnotation: "user",
+		},
+		Args: cobra.ExactArgs(0),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info, err := (*api).GetTokenInfoContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if info == nil {
+				return errors.New("failed getting token info")
+			}
+			out := cmd.OutOrStdout()
+			if info.ResourceOwnerID == nil {
+				_, _ = fmt.Fprintf(out, "Application: %s\n", info.Application.UID)
+			} else {
+				user, err := (*api).GetMeContext(cmd.Context())
+				if err != nil {
+					return err
+				}
+				if user == nil {
+					return errors.New("failed getting the logged in user")
+				}
+				_, _ = fmt.Fprintf(out, "Login: %s\n", user.Login)
+				roles := make([]string, 0, len(user.Roles))
+				for _, role := range user.Roles {
+					roles = append(roles, role.Name)
+				}
+				if user.IsStaff {
+					roles = append(roles, "staff")
+				}
+				_, _ = fmt.Fprintf(out, "Roles: %s\n", strings.Join(roles, ", "))
+				if campus := user.GetPrimaryCampus(); campus != nil {
+					_, _ = fmt.Fprintf(out, "Campus: %s (%d)\n", campus.Name, campus.ID)
+				}
+			}
+			_, _ = fmt.Fprintf(out, "Scopes: %s\n", strings.Join(info.Scopes, ", "))
+			return nil
+		},
+	}
+}
+
+var whoamiCmd = NewWhoamiCmd(&API)
+
+func init() {
+	rootCmd.AddCommand(whoamiCmd)
+}
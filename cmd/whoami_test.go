@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"goft/pkg/ftapi"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type whoamiMockAPI struct {
+	baseMockAPI
+	ownerID *int
+}
+
+func (m *whoamiMockAPI) GetTokenInfoContext(ctx context.Context) (*ftapi.TokenInfo, error) {
+	info := &ftapi.TokenInfo{ResourceOwnerID: m.ownerID, Scopes: []string{"public", "profile"}}
+	info.Application.UID = "3089cd"
+	return info, nil
+}
+func (m *whoamiMockAPI) GetMeContext(ctx context.Context) (*ftapi.User, error) {
+	return &ftapi.User{Login: "spoody", IsStaff: true}, nil
+}
+
+func TestWhoami(t *testing.T) {
+	id := 66356
+	var api ftapi.APIInterface = &whoamiMockAPI{ownerID: &id}
+	stdout := bytes.NewBufferString("")
+	testCmd := NewWhoamiCmd(&api)
+	testCmd.SetArgs([]string{})
+	testCmd.SetOut(stdout)
+	assert.Nil(t, testCmd.Execute())
+	assert.Equal(t, "Login: spoody\nRoles: staff\nScopes: public, profile\n", stdout.String())
+}
+
+func TestWhoamiWithApplicationToken(t *testing.T) {
+	var api ftapi.APIInterface = &whoamiMockAPI{}
+	stdout := bytes.NewBufferString("")
+	testCmd := NewWhoamiCmd(&api)
+	testCmd.SetArgs([]string{})
+	testCmd.SetOut(stdout)
+	assert.Nil(t, testCmd.Execute())
+	assert.Equal(t, "Application: 3089cd\nScopes: public, profile\n", stdout.String())
+}
+
+func TestResolveLogin(t *testing.T) {
+	var api ftapi.APIInterface = &whoamiMockAPI{}
+	login, err := resolveLogin(context.Background(), api, "me")
+	assert.Nil(t, err)
+	assert.Equal(t, "spoody", login)
+	login, err = resolveLogin(context.Background(), api, "norminet")
+	assert.Nil(t, err)
+	assert.Equal(t, "norminet", login)
+}
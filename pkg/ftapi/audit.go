@@ -0,0 +1,181 @@
+package ftapi
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// redacted replaces the values of sensitive fields in the audit log
+const redacted = "[REDACTED]"
+
+// AuditEntry is a write request sent to the API, as recorded in the audit log
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	Profile  string    `json:"profile,omitempty"`
+	Operator string    `json:"operator,omitempty"`
+	Method   string    `json:"method"`
+	Endpoint string    `json:"endpoint"`
+	// Payload is the JSON body of the request with the passwords redacted,
+	// other bodies are described as a string
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	Status    int             `json:"status,omitempty"`
+	RequestID string          `json:"request_id,omitempty"`
+	// Error is set when no response was received
+	Error string `json:"error,omitempty"`
+}
+
+// AuditLog appends an AuditEntry for every write request to a JSON lines file
+type AuditLog struct {
+	Path    string
+	Profile string
+	// Operator returns the login of the person running the requests, it is called once on the first write
+	Operator func() string
+
+	mu           sync.Mutex
+	operatorOnce sync.Once
+	operator     string
+}
+
+// NewAuditLog creates an AuditLog appending to the file at path
+func NewAuditLog(path string, profile string, operator func() string) *AuditLog {
+	return &AuditLog{Path: path, Profile: profile, Operator: operator}
+}
+
+// WithAuditLog records the write requests sent by the API in log
+func WithAuditLog(log *AuditLog) Option {
+	return func(ft *API) {
+		ft.auditLog = log
+	}
+}
+
+// Append adds entry to the log, the file is created with 0600 permissions if needed
+func (l *AuditLog) Append(entry AuditEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	err = os.MkdirAll(filepath.Dir(l.Path), 0700)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(l.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(data, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Read returns the entries of the log in the order they were written, an empty log is not an error
+func (l *AuditLog) Read() ([]AuditEntry, error) {
+	file, err := os.Open(l.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", l.Path, line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+// record builds the entry of a request sent with payload and appends it to the log
+func (l *AuditLog) record(req *http.Request, payload []byte, resp *http.Response, sendErr error) error {
+	l.operatorOnce.Do(func() {
+		if l.Operator != nil {
+			l.operator = l.Operator()
+		}
+	})
+	entry := AuditEntry{
+		Time:     time.Now(),
+		Profile:  l.Profile,
+		Operator: l.operator,
+		Method:   req.Method,
+		Endpoint: req.URL.Path,
+		Payload:  auditPayload(req.Header.Get("Content-Type"), payload),
+	}
+	if resp != nil {
+		entry.Status = resp.StatusCode
+		entry.RequestID = resp.Header.Get("X-Request-Id")
+	}
+	if sendErr != nil {
+		entry.Error = sendErr.Error()
+	}
+	return l.Append(entry)
+}
+
+// auditPayload returns the JSON payload with its passwords redacted, or a description of other payloads
+func auditPayload(contentType string, payload []byte) json.RawMessage {
+	if len(payload) == 0 {
+		return nil
+	}
+	var data interface{}
+	if strings.HasPrefix(contentType, "application/json") && json.Unmarshal(payload, &data) == nil {
+		clean, err := json.Marshal(redactPasswords(data))
+		if err == nil {
+			return clean
+		}
+	}
+	description, _ := json.Marshal(fmt.Sprintf("<%d bytes of %s>", len(payload), contentType))
+	return description
+}
+
+// redactPasswords replaces the values of the keys containing "password" in a decoded JSON value
+func redactPasswords(data interface{}) interface{} {
+	switch value := data.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			if strings.Contains(strings.ToLower(key), "password") {
+				value[key] = redacted
+			} else {
+				value[key] = redactPasswords(field)
+			}
+		}
+	case []interface{}:
+		for i, element := range value {
+			value[i] = redactPasswords(element)
+		}
+	}
+	return data
+}
+
+// requestPayload returns a copy of the body of req without consuming it
+func requestPayload(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	payload, _ := ioutil.ReadAll(body)
+	return payload
+}
@@ -0,0 +1,63 @@
+package ftapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Request-Id", "request-"+req.Method)
+		switch req.Method {
+		case "GET":
+			rw.WriteHeader(http.StatusOK)
+			_, _ = rw.Write([]byte(`{"id":66356,"login":"spoody"}`))
+		case "PATCH":
+			rw.WriteHeader(http.StatusForbidden)
+		default:
+			rw.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+	operatorCalls := 0
+	auditLog := NewAuditLog(filepath.Join(t.TempDir(), "goft", "audit.jsonl"), "bocal", func() string {
+		operatorCalls++
+		return "mbounya"
+	})
+	ftAPI := New(server.URL, server.Client(), WithAuditLog(auditLog))
+
+	_, err := ftAPI.GetUserByLogin("spoody")
+	assert.Nil(t, err)
+	resp, err := ftAPI.Post("/users/spoody/reset_password", "application/json", strings.NewReader(`{"user":{"password":"hunter2","login":"spoody"}}`))
+	assert.Nil(t, err)
+	resp.Body.Close()
+	err = ftAPI.UpdateUser("spoody", &User{Kind: "admin"})
+	assert.NotNil(t, err)
+
+	entries, err := auditLog.Read()
+	assert.Nil(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, 1, operatorCalls)
+	assert.Equal(t, "bocal", entries[0].Profile)
+	assert.Equal(t, "mbounya", entries[0].Operator)
+	assert.Equal(t, "POST", entries[0].Method)
+	assert.Equal(t, "/users/spoody/reset_password", entries[0].Endpoint)
+	assert.JSONEq(t, `{"user":{"password":"[REDACTED]","login":"spoody"}}`, string(entries[0].Payload))
+	assert.Equal(t, http.StatusNoContent, entries[0].Status)
+	assert.Equal(t, "request-POST", entries[0].RequestID)
+	assert.Equal(t, "PATCH", entries[1].Method)
+	assert.Equal(t, "/users/spoody", entries[1].Endpoint)
+	assert.JSONEq(t, `{"user":{"kind":"admin"}}`, string(entries[1].Payload))
+	assert.Equal(t, http.StatusForbidden, entries[1].Status)
+}
+
+func TestAuditLogReadMissing(t *testing.T) {
+	entries, err := NewAuditLog(filepath.Join(t.TempDir(), "audit.jsonl"), "", nil).Read()
+	assert.Nil(t, err)
+	assert.Empty(t, entries)
+}
@@ -1,6 +1,10 @@
 package ftapi
 
-import "time"
+import (
+	"strings"
+	"time"
+	"unicode"
+)
 
 type language struct {
 	ID int `json:"id,omitempty"`
@@ -18,4 +22,56 @@ type Campus struct {
 	Language *language `json:"language,omitempty"`
 	UsersCount int `json:"users_count,omitempty"`
 	VogsphereID int `json:"vogsphere_id,omitempty"`
+	Country string `json:"country,omitempty"`
+	City string `json:"city,omitempty"`
+	Address string `json:"address,omitempty"`
+	Zip string `json:"zip,omitempty"`
+	Website string `json:"website,omitempty"`
+	Active bool `json:"active,omitempty"`
+	Public bool `json:"public,omitempty"`
+}
+
+// Slug returns the campus name in lower case with dashes instead of spaces and punctuation, like "sao-paulo"
+func (c *Campus) Slug() string {
+	return Slugify(c.Name)
+}
+
+// Location returns the time zone of the campus, UTC when it isn't set
+func (c *Campus) Location() (*time.Location, error) {
+	if c.TimeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(c.TimeZone)
+}
+
+// Slugify lowers s, strips its accents and replaces anything else than letters and digits by single dashes
+func Slugify(s string) string {
+	var slug strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if replacement, ok := accents[r]; ok {
+			r = replacement
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			dash = false
+			slug.WriteRune(r)
+			continue
+		}
+		dash = true
+	}
+	return slug.String()
+}
+
+// accents maps the accented letters of campus names to their base letter
+var accents = map[rune]rune{
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a',
+	'ç': 'c',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i',
+	'ñ': 'n',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u',
 }
@@ -0,0 +1,33 @@
+package ftapi
+
+import "time"
+
+// Cursus represents a cursus entity, like 42cursus or the C Piscine
+type Cursus struct {
+	ID int `json:"id,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	Name string `json:"name,omitempty"`
+	Slug string `json:"slug,omitempty"`
+	Kind string `json:"kind,omitempty"`
+}
+
+type skill struct {
+	ID int `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	Level float32 `json:"level,omitempty"`
+}
+
+// CursusUser represents the enrollment of a user in a cursus
+type CursusUser struct {
+	ID int `json:"id,omitempty"`
+	Grade string `json:"grade,omitempty"`
+	Level float32 `json:"level,omitempty"`
+	Skills []*skill `json:"skills,omitempty"`
+	BlackholedAt *time.Time `json:"blackholed_at,omitempty"`
+	BeginAt *time.Time `json:"begin_at,omitempty"`
+	EndAt *time.Time `json:"end_at,omitempty"`
+	HasCoalition bool `json:"has_coalition,omitempty"`
+	CursusID int `json:"cursus_id,omitempty"`
+	Cursus *Cursus `json:"cursus,omitempty"`
+	User *User `json:"user,omitempty"`
+}
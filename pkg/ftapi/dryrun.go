@@ -0,0 +1,75 @@
+package ftapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// dryRunHeader marks the responses made up for the requests that were not sent in dry run mode
+const dryRunHeader = "X-Goft-Dry-Run"
+
+// WithDryRun makes the API print the write requests to w instead of sending them.
+// GET requests are still sent so the lookups needed to build the writes work,
+// write methods then behave as if the intra accepted the request
+func WithDryRun(w io.Writer) Option {
+	return func(ft *API) {
+		ft.dryRun = w
+	}
+}
+
+// isWrite returns true for the methods that modify data on the intra
+func isWrite(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// printDryRun prints the method, url and payload of req and returns a made up empty response
+func (ft *API) printDryRun(req *http.Request) (*http.Response, error) {
+	_, _ = fmt.Fprintf(ft.dryRun, "%s %s\n", req.Method, req.URL)
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		contentType := req.Header.Get("Content-Type")
+		var pretty bytes.Buffer
+		switch {
+		case strings.HasPrefix(contentType, "application/json") && json.Indent(&pretty, body, "", "\t") == nil:
+			_, _ = fmt.Fprintf(ft.dryRun, "%s\n", pretty.String())
+		case len(body) > 0:
+			_, _ = fmt.Fprintf(ft.dryRun, "<%d bytes of %s>\n", len(body), contentType)
+		}
+	}
+	header := http.Header{}
+	header.Set(dryRunHeader, "true")
+	header.Set("Content-Type", "application/json")
+	return &http.Response{
+		Status:     "200 OK (dry run)",
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+// IsDryRun returns true if resp was made up because its request was not sent in dry run mode
+func IsDryRun(resp *http.Response) bool {
+	return resp != nil && resp.Header.Get(dryRunHeader) != ""
+}
+
+// expectStatus returns an APIError unless resp has the given status or was made up in dry run mode
+func expectStatus(resp *http.Response, status int) error {
+	if resp.StatusCode == status || IsDryRun(resp) {
+		return nil
+	}
+	return newAPIError(resp)
+}
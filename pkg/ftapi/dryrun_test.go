@@ -0,0 +1,56 @@
+package ftapi
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Method != "GET" {
+			t.Fatalf("%s request sent in dry run mode", req.Method)
+		}
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte(`{"id":66356,"login":"spoody"}`))
+	}))
+	defer server.Close()
+	out := bytes.NewBufferString("")
+	ftAPI := New(server.URL, server.Client(), WithDryRun(out))
+
+	user, err := ftAPI.GetUserByLogin("spoody")
+	assert.Nil(t, err)
+	assert.Equal(t, 66356, user.ID)
+
+	err = ftAPI.CreateUser(&User{Login: "spoody", Email: "spoody@test.local", FirstName: "Spooder", LastName: "Webz", Kind: "admin"}, 21)
+	assert.Nil(t, err)
+	err = ftAPI.UpdateUser("spoody", &User{Kind: "student"})
+	assert.Nil(t, err)
+	resp, err := ftAPI.Post("/raw", "text/plain", strings.NewReader("raw payload"))
+	assert.Nil(t, err)
+	assert.True(t, IsDryRun(resp))
+	assert.Equal(t, `POST `+server.URL+`/users
+{
+	"user": {
+		"campus_id": 21,
+		"email": "spoody@test.local",
+		"first_name": "Spooder",
+		"kind": "admin",
+		"last_name": "Webz",
+		"login": "spoody"
+	}
+}
+PATCH `+server.URL+`/users/spoody
+{
+	"user": {
+		"kind": "student"
+	}
+}
+POST `+server.URL+`/raw
+<11 bytes of text/plain>
+`, out.String())
+}
@@ -0,0 +1,122 @@
+package ftapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// Sentinel errors matched by an *APIError, use them with errors.Is:
+//
+//	if errors.Is(err, ftapi.ErrNotFound) { ... }
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrValidation   = errors.New("validation failed")
+)
+
+// APIError is returned when the 42 API answers with an unexpected status code
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	// RequestID is the X-Request-Id set by the intra, useful when reporting an issue
+	RequestID string
+	// Message is the error message sent by the intra, if any
+	Message string
+	// Errors holds the validation errors per field, e.g. {"email": ["has already been taken"]}
+	Errors map[string][]string
+	// Body is the raw response body
+	Body []byte
+}
+
+// newAPIError builds an APIError from resp, it reads but doesn't close the body
+func newAPIError(resp *http.Response) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get("X-Request-Id"),
+	}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.Path = resp.Request.URL.Path
+	}
+	if resp.Body != nil {
+		apiErr.Body, _ = ioutil.ReadAll(resp.Body)
+	}
+	apiErr.parseBody()
+	return apiErr
+}
+
+// parseBody extracts the message and validation errors from the intra's JSON error body,
+// which is either {"error": "...", "message": "..."}, {"errors": {"field": [...]}} or {"field": [...]}
+func (e *APIError) parseBody() {
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(e.Body, &body); err != nil {
+		return
+	}
+	for _, key := range []string{"message", "error"} {
+		var msg string
+		if json.Unmarshal(body[key], &msg) == nil && msg != "" {
+			e.Message = msg
+			break
+		}
+	}
+	fields := body
+	if nested, ok := body["errors"]; ok {
+		fields = nil
+		_ = json.Unmarshal(nested, &fields)
+	}
+	for field, raw := range fields {
+		var messages []string
+		if json.Unmarshal(raw, &messages) != nil || len(messages) == 0 {
+			continue
+		}
+		if e.Errors == nil {
+			e.Errors = map[string][]string{}
+		}
+		e.Errors[field] = messages
+	}
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if len(e.Errors) > 0 {
+		fields := make([]string, 0, len(e.Errors))
+		for field := range e.Errors {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		details := make([]string, 0, len(fields))
+		for _, field := range fields {
+			details = append(details, field+" "+strings.Join(e.Errors[field], ", "))
+		}
+		msg += ": " + strings.Join(details, "; ")
+	}
+	return msg
+}
+
+// Is reports whether the error matches one of the sentinel errors
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrValidation:
+		return e.StatusCode == http.StatusUnprocessableEntity
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package ftapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIErrorWithValidationErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Request-Id", "4a2e1b")
+		rw.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = rw.Write([]byte(`{"email":["has already been taken","is invalid"],"login":["is too long"]}`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	err := ftAPI.CreateUser(&User{Login: "spoody"}, 21)
+	assert.NotNil(t, err)
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.False(t, errors.Is(err, ErrNotFound))
+
+	var apiErr *APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "POST", apiErr.Method)
+	assert.Equal(t, "/users", apiErr.Path)
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.StatusCode)
+	assert.Equal(t, "4a2e1b", apiErr.RequestID)
+	assert.Equal(t, []string{"has already been taken", "is invalid"}, apiErr.Errors["email"])
+	assert.Equal(t, "POST /users: 422 Unprocessable Entity: email has already been taken, is invalid; login is too long", err.Error())
+}
+
+func TestAPIErrorWithMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusForbidden)
+		_, _ = rw.Write([]byte(`{"error":"Forbidden","message":"You are not authorized to access this page."}`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	_, err := ftAPI.GetUserByLogin("spoody")
+	assert.True(t, errors.Is(err, ErrForbidden))
+	assert.Equal(t, "GET /users/spoody: 403 Forbidden: You are not authorized to access this page.", err.Error())
+}
+
+func TestAPIErrorWithNestedErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusUnauthorized)
+		_, _ = rw.Write([]byte(`{"errors":{"token":["is expired"]}}`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	err := ftAPI.AddCorrectionPoints("spoody", 1, "Testing")
+	assert.True(t, errors.Is(err, ErrUnauthorized))
+	assert.Equal(t, "POST /users/spoody/correction_points/add: 401 Unauthorized: token is expired", err.Error())
+}
@@ -7,9 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -20,53 +22,146 @@ import (
 )
 
 // APIInterface interface for a struct that talks to the 42 API
+// Every method has a XxxContext variant that uses the given context for the underlying requests
 type APIInterface interface {
 	Get(url string) (*http.Response, error)
+	GetContext(ctx context.Context, url string) (*http.Response, error)
 	Delete(url string, contentType string, body io.Reader) (*http.Response, error)
+	DeleteContext(ctx context.Context, url string, contentType string, body io.Reader) (*http.Response, error)
 	Post(url string, contentType string, body io.Reader) (resp *http.Response, err error)
+	PostContext(ctx context.Context, url string, contentType string, body io.Reader) (resp *http.Response, err error)
 	PostJSON(url string, data interface{}) (resp *http.Response, err error)
+	PostJSONContext(ctx context.Context, url string, data interface{}) (resp *http.Response, err error)
 	Patch(url string, contentType string, body io.Reader) (resp *http.Response, err error)
+	PatchContext(ctx context.Context, url string, contentType string, body io.Reader) (resp *http.Response, err error)
 	PatchJSON(url string, data interface{}) (resp *http.Response, err error)
+	PatchJSONContext(ctx context.Context, url string, data interface{}) (resp *http.Response, err error)
 
 	CreateUser(user *User, campusID int) error
+	CreateUserContext(ctx context.Context, user *User, campusID int) error
 	SetUserImage(login string, img *os.File) error
+	SetUserImageContext(ctx context.Context, login string, img *os.File) error
 	CreateClose(close *Close) error
+	CreateCloseContext(ctx context.Context, close *Close) error
 	GetUserByLogin(login string) (*User, error)
+	GetUserByLoginContext(ctx context.Context, login string) (*User, error)
+	GetUserByEmail(email string) (*User, error)
+	GetUserByEmailContext(ctx context.Context, email string) (*User, error)
+	GetMe() (*User, error)
+	GetMeContext(ctx context.Context) (*User, error)
+	GetTokenInfo() (*TokenInfo, error)
+	GetTokenInfoContext(ctx context.Context) (*TokenInfo, error)
 	UpdateUser(login string, data *User) error
+	UpdateUserContext(ctx context.Context, login string, data *User) error
 
 	AddCorrectionPoints(login string, points uint, reason string) error
+	AddCorrectionPointsContext(ctx context.Context, login string, points uint, reason string) error
 	RemoveCorrectionPoints(login string, points uint, reason string) error
+	RemoveCorrectionPointsContext(ctx context.Context, login string, points uint, reason string) error
 
 	GetUserAgus(login string) ([]Agu, error)
+	GetUserAgusContext(ctx context.Context, login string) ([]Agu, error)
 	CreateFreePastAgu(login string, duration int, reason string) error
+	CreateFreePastAguContext(ctx context.Context, login string, duration int, reason string) error
 
 	GetProjectByName(name string) (*Project, error)
+	GetProjectByNameContext(ctx context.Context, name string) (*Project, error)
 	GetUserProjects(login string, filter_param map[string]string, range_param map[string]string, page_number int) ([]*ProjectUser, error)
+	GetUserProjectsContext(ctx context.Context, login string, filter_param map[string]string, range_param map[string]string, page_number int) ([]*ProjectUser, error)
+	ListUserProjects(login string, filter_param map[string]string, range_param map[string]string) *Pager
+	ListUserProjectsContext(ctx context.Context, login string, filter_param map[string]string, range_param map[string]string) *Pager
+
+	GetCampus(id int) (*Campus, error)
+	GetCampusContext(ctx context.Context, id int) (*Campus, error)
+	ListCampuses(params url.Values) *Pager
+	ListCampusesContext(ctx context.Context, params url.Values) *Pager
+	ListCampusUsers(campusID int, params url.Values) *Pager
+	ListCampusUsersContext(ctx context.Context, campusID int, params url.Values) *Pager
+	ListCursusUsers(cursusID int, params url.Values) *Pager
+	ListCursusUsersContext(ctx context.Context, cursusID int, params url.Values) *Pager
+
+	ListCampusCursusUsers(campusID int, params url.Values) *Pager
+	ListCampusCursusUsersContext(ctx context.Context, campusID int, params url.Values) *Pager
+	ListCampusLocations(campusID int, params url.Values) *Pager
+	ListCampusLocationsContext(ctx context.Context, campusID int, params url.Values) *Pager
+	ListUserLocations(login string, params url.Values) *Pager
+	ListUserLocationsContext(ctx context.Context, login string, params url.Values) *Pager
+	ListCursus(params url.Values) *Pager
+	ListCursusContext(ctx context.Context, params url.Values) *Pager
+	GetUserCursus(login string) ([]*CursusUser, error)
+	GetUserCursusContext(ctx context.Context, login string) ([]*CursusUser, error)
+	GetCursusUser(id int) (*CursusUser, error)
+	GetCursusUserContext(ctx context.Context, id int) (*CursusUser, error)
+	AddUserToCursus(userID int, cursusID int, beginAt time.Time, endAt *time.Time) (*CursusUser, error)
+	AddUserToCursusContext(ctx context.Context, userID int, cursusID int, beginAt time.Time, endAt *time.Time) (*CursusUser, error)
+	UpdateCursusUser(id int, data *CursusUser) error
+	UpdateCursusUserContext(ctx context.Context, id int, data *CursusUser) error
+
+	Paginate(url string, params url.Values) *Pager
+	PaginateContext(ctx context.Context, url string, params url.Values) *Pager
+
+	RateLimit() RateLimit
 }
 
 // API This is a struct to send authenticated requests to the 42 API
 type API struct {
 	apiEndpoint string
 	httpClient  *http.Client
+	limiter     *RateLimiter
+	retryPolicy RetryPolicy
+	// dryRun receives the write requests instead of the intra when set, see WithDryRun
+	dryRun io.Writer
+	// auditLog records the write requests when set, see WithAuditLog
+	auditLog *AuditLog
+}
+
+// Option configures an API instance created with New
+type Option func(ft *API)
+
+// WithRateLimiter makes the API wait on limiter before sending requests,
+// passing the same limiter to several instances makes them share the budget.
+// A nil limiter disables client side rate limiting
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(ft *API) {
+		ft.limiter = limiter
+	}
 }
 
 // New Creates an API instance
-func New(apiEndpoint string, authenticatedClient *http.Client) APIInterface {
-	return &API{
+func New(apiEndpoint string, authenticatedClient *http.Client, options ...Option) APIInterface {
+	ft := &API{
 		apiEndpoint: apiEndpoint,
 		httpClient:  authenticatedClient,
+		limiter:     NewRateLimiter(DefaultSecondlyLimit, DefaultHourlyLimit),
+		retryPolicy: DefaultRetryPolicy,
+	}
+	for _, option := range options {
+		option(ft)
 	}
+	return ft
 }
 
 // NewFromCredentials Creates an API instance with an authenticated client using the given oAuth2 credentials
-func NewFromCredentials(apiEndpoint string, oauthCredentials *clientcredentials.Config) APIInterface {
-	ctx := context.Background()
+func NewFromCredentials(apiEndpoint string, oauthCredentials *clientcredentials.Config, options ...Option) APIInterface {
+	return NewFromCredentialsContext(context.Background(), apiEndpoint, oauthCredentials, options...)
+}
+
+// NewFromCredentialsContext is the same as NewFromCredentials but ctx is used when fetching access tokens
+func NewFromCredentialsContext(ctx context.Context, apiEndpoint string, oauthCredentials *clientcredentials.Config, options ...Option) APIInterface {
 	authenticatedClient := oauthCredentials.Client(ctx)
-	return New(apiEndpoint, authenticatedClient)
+	return New(apiEndpoint, authenticatedClient, options...)
 }
 
-func (ft *API) newRequest(method string, contentType string, url string, body io.Reader) (*http.Request, error) {
-	req, err := http.NewRequest(method, url, body)
+// RateLimit returns the remaining request budget, as tracked by the client side rate limiter
+func (ft *API) RateLimit() RateLimit {
+	if ft.limiter == nil {
+		return RateLimit{}
+	}
+	return ft.limiter.Status()
+}
+
+func (ft *API) newRequest(ctx context.Context, method string, contentType string, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, err
 	}
@@ -75,27 +170,75 @@ func (ft *API) newRequest(method string, contentType string, url string, body io
 	return req, nil
 }
 
-// Execute the request
+// Execute the request, write requests are printed in dry run mode and recorded in the audit log
 func (ft *API) do(req *http.Request) (*http.Response, error) {
-	for {
+	if !isWrite(req.Method) {
+		return ft.send(req)
+	}
+	if ft.dryRun != nil {
+		return ft.printDryRun(req)
+	}
+	if ft.auditLog == nil {
+		return ft.send(req)
+	}
+	payload := requestPayload(req)
+	resp, err := ft.send(req)
+	// The request was sent, failing to record it must not hide its result
+	if auditErr := ft.auditLog.record(req, payload, resp, err); auditErr != nil {
+		log.Print("audit log:", auditErr)
+	}
+	return resp, err
+}
+
+// send executes the request, waiting on the rate limiter and retrying transient failures
+func (ft *API) send(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	attempt := 0
+	for sent := false; ; sent = true {
+		if sent {
+			if err := rewindBody(req); err != nil {
+				return nil, err
+			}
+		}
+		if ft.limiter != nil {
+			if err := ft.limiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
 		resp, err := ft.httpClient.Do(req)
-		if err != nil {
-			return resp, err
+		if err == nil && ft.limiter != nil {
+			ft.limiter.Update(resp.Header)
 		}
-		if resp.StatusCode == http.StatusTooManyRequests {
+		if err == nil && resp.StatusCode == http.StatusTooManyRequests {
 			// Check if exceeded max hourly rate
 			retryAfter, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
-			if err != nil {
-				retryAfter = 0
-			}
 			if retryAfter <= 0 {
-				return nil, errors.New("exceeded rate limit")
+				defer resp.Body.Close()
+				return nil, newAPIError(resp)
+			}
+			// We wait for the duration set by the header, unless the request is cancelled
+			resp.Body.Close()
+			if err := sleepContext(req.Context(), time.Duration(retryAfter)*time.Second); err != nil {
+				return nil, err
 			}
-			// We wait for the duration set by the header
-			time.Sleep(time.Duration(retryAfter) * time.Second)
 			continue
 		}
-		return resp, err
+		// Being throttled is not a failure, only other responses count as attempts
+		attempt++
+		if !ft.retryPolicy.shouldRetry(req, resp, err, attempt) {
+			return resp, err
+		}
+		delay := ft.retryPolicy.backoff(attempt)
+		if ft.retryPolicy.MaxElapsedTime > 0 && time.Since(start)+delay > ft.retryPolicy.MaxElapsedTime {
+			return resp, err
+		}
+		if resp != nil {
+			_, _ = io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleepContext(req.Context(), delay); err != nil {
+			return nil, err
+		}
 	}
 }
 
@@ -105,7 +248,12 @@ func parseJSON(body io.ReadCloser, target interface{}) error {
 
 // Get sends a get request to the given URL
 func (ft *API) Get(url string) (*http.Response, error) {
-	req, err := http.NewRequest("GET", ft.apiEndpoint+url, nil)
+	return ft.GetContext(context.Background(), url)
+}
+
+// GetContext is the same as Get but uses ctx for the underlying requests
+func (ft *API) GetContext(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", ft.apiEndpoint+url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -114,7 +262,12 @@ func (ft *API) Get(url string) (*http.Response, error) {
 
 // Post sends a POST request to the given url
 func (ft *API) Post(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	req, err := ft.newRequest("POST", contentType, ft.apiEndpoint+url, body)
+	return ft.PostContext(context.Background(), url, contentType, body)
+}
+
+// PostContext is the same as Post but uses ctx for the underlying requests
+func (ft *API) PostContext(ctx context.Context, url string, contentType string, body io.Reader) (resp *http.Response, err error) {
+	req, err := ft.newRequest(ctx, "POST", contentType, ft.apiEndpoint+url, body)
 	if err != nil {
 		return nil, err
 	}
@@ -123,7 +276,12 @@ func (ft *API) Post(url string, contentType string, body io.Reader) (resp *http.
 
 // Patch sends a PATCH request to the given url
 func (ft *API) Patch(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	req, err := ft.newRequest("PATCH", contentType, ft.apiEndpoint+url, body)
+	return ft.PatchContext(context.Background(), url, contentType, body)
+}
+
+// PatchContext is the same as Patch but uses ctx for the underlying requests
+func (ft *API) PatchContext(ctx context.Context, url string, contentType string, body io.Reader) (resp *http.Response, err error) {
+	req, err := ft.newRequest(ctx, "PATCH", contentType, ft.apiEndpoint+url, body)
 	if err != nil {
 		return nil, err
 	}
@@ -132,7 +290,12 @@ func (ft *API) Patch(url string, contentType string, body io.Reader) (resp *http
 
 // Delete sends a DELETE request to the given url
 func (ft *API) Delete(url string, contentType string, body io.Reader) (resp *http.Response, err error) {
-	req, err := ft.newRequest("DELETE", contentType, ft.apiEndpoint+url, body)
+	return ft.DeleteContext(context.Background(), url, contentType, body)
+}
+
+// DeleteContext is the same as Delete but uses ctx for the underlying requests
+func (ft *API) DeleteContext(ctx context.Context, url string, contentType string, body io.Reader) (resp *http.Response, err error) {
+	req, err := ft.newRequest(ctx, "DELETE", contentType, ft.apiEndpoint+url, body)
 	if err != nil {
 		return nil, err
 	}
@@ -141,36 +304,54 @@ func (ft *API) Delete(url string, contentType string, body io.Reader) (resp *htt
 
 // PatchJSON this method will automatically turn data into a json and send a PATCH request to the given url
 func (ft *API) PatchJSON(url string, data interface{}) (resp *http.Response, err error) {
+	return ft.PatchJSONContext(context.Background(), url, data)
+}
+
+// PatchJSONContext is the same as PatchJSON but uses ctx for the underlying requests
+func (ft *API) PatchJSONContext(ctx context.Context, url string, data interface{}) (resp *http.Response, err error) {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return nil, err
 	}
-	return ft.Patch(url, "application/json", bytes.NewReader(jsonData))
+	return ft.PatchContext(ctx, url, "application/json", bytes.NewReader(jsonData))
 }
 
 // PostJSON this method will automatically turn data into a json and send a post request to the given url
 func (ft *API) PostJSON(url string, data interface{}) (resp *http.Response, err error) {
+	return ft.PostJSONContext(context.Background(), url, data)
+}
+
+// PostJSONContext is the same as PostJSON but uses ctx for the underlying requests
+func (ft *API) PostJSONContext(ctx context.Context, url string, data interface{}) (resp *http.Response, err error) {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return nil, err
 	}
-	return ft.Post(url, "application/json", bytes.NewReader(jsonData))
+	return ft.PostContext(ctx, url, "application/json", bytes.NewReader(jsonData))
 }
 
 // DeleteJSON this method will automatically turn data into a json and send a post request to the given url
 func (ft *API) DeleteJSON(url string, data interface{}) (resp *http.Response, err error) {
+	return ft.DeleteJSONContext(context.Background(), url, data)
+}
+
+// DeleteJSONContext is the same as DeleteJSON but uses ctx for the underlying requests
+func (ft *API) DeleteJSONContext(ctx context.Context, url string, data interface{}) (resp *http.Response, err error) {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return nil, err
 	}
-	return ft.Delete(url, "application/json", bytes.NewReader(jsonData))
+	return ft.DeleteContext(ctx, url, "application/json", bytes.NewReader(jsonData))
 }
 
-// TODO: better handle errors
-
-// CreateUser creates a new user and sets `user` id and url to the one returned by the API
+// CreateUser creates a new user and sets `user` id, url and login to the ones returned by the API
 // Following fields are required: login, email, first_name, last_name, kind
 func (ft *API) CreateUser(user *User, campusID int) error {
+	return ft.CreateUserContext(context.Background(), user, campusID)
+}
+
+// CreateUserContext is the same as CreateUser but uses ctx for the underlying requests
+func (ft *API) CreateUserContext(ctx context.Context, user *User, campusID int) error {
 	// Prepare payload format
 	payload := map[string]map[string]interface{}{
 		"user": {
@@ -182,22 +363,32 @@ func (ft *API) CreateUser(user *User, campusID int) error {
 			"campus_id":  campusID,
 		},
 	}
-	resp, err := ft.PostJSON("/users", payload)
+	resp, err := ft.PostJSONContext(ctx, "/users", payload)
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode != http.StatusCreated {
-		return errors.New("failed creating user")
+	defer resp.Body.Close()
+	if err := expectStatus(resp, http.StatusCreated); err != nil {
+		return err
 	}
 	var createdUser User
 	_ = json.NewDecoder(resp.Body).Decode(&createdUser)
 	user.ID = createdUser.ID
 	user.URL = createdUser.URL
+	// The login is generated by the intra when it is not set
+	if createdUser.Login != "" {
+		user.Login = createdUser.Login
+	}
 	return nil
 }
 
 // SetUserImage set a profile image to the user
 func (ft *API) SetUserImage(login string, img *os.File) error {
+	return ft.SetUserImageContext(context.Background(), login, img)
+}
+
+// SetUserImageContext is the same as SetUserImage but uses ctx for the underlying requests
+func (ft *API) SetUserImageContext(ctx context.Context, login string, img *os.File) error {
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 	part, err := writer.CreateFormFile("user[image]", filepath.Base(img.Name()))
@@ -212,23 +403,24 @@ func (ft *API) SetUserImage(login string, img *os.File) error {
 	if err != nil {
 		return err
 	}
-	resp, err := ft.Patch("/users/"+login, writer.FormDataContentType(), body)
+	resp, err := ft.PatchContext(ctx, "/users/"+login, writer.FormDataContentType(), body)
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode != http.StatusNoContent {
-		switch resp.StatusCode {
-		case http.StatusNotFound:
-			return errors.New("user not found")
-		default:
-			return errors.New("failed setting profile image")
-		}
+	defer resp.Body.Close()
+	if err := expectStatus(resp, http.StatusNoContent); err != nil {
+		return err
 	}
 	return nil
 }
 
 // CreateClose creates a new close for the close.user, following properties must be set: close.Kind, close.Reason and close.User.Login
 func (ft *API) CreateClose(close *Close) error {
+	return ft.CreateCloseContext(context.Background(), close)
+}
+
+// CreateCloseContext is the same as CreateClose but uses ctx for the underlying requests
+func (ft *API) CreateCloseContext(ctx context.Context, close *Close) error {
 	payload := map[string]map[string]interface{}{
 		"close": {
 			"kind":   close.Kind,
@@ -242,35 +434,72 @@ func (ft *API) CreateClose(close *Close) error {
 		return errors.New("close must contain a closer")
 	}
 	payload["close"]["closer_id"] = close.Closer.ID
-	resp, err := ft.PostJSON("/users/"+close.User.Login+"/closes", payload)
+	resp, err := ft.PostJSONContext(ctx, "/users/"+close.User.Login+"/closes", payload)
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode == http.StatusCreated {
-		return nil
-	}
-	switch resp.StatusCode {
-	case http.StatusNotFound:
-		return errors.New("user not found")
-	default:
-		return errors.New("failed creating close")
+	defer resp.Body.Close()
+	if err := expectStatus(resp, http.StatusCreated); err != nil {
+		return err
 	}
+	return nil
 }
 
 // GetUserByLogin gets a user by the provided login
 func (ft *API) GetUserByLogin(login string) (*User, error) {
-	resp, err := ft.Get("/users/" + login)
+	return ft.GetUserByLoginContext(context.Background(), login)
+}
+
+// GetUserByLoginContext is the same as GetUserByLogin but uses ctx for the underlying requests
+func (ft *API) GetUserByLoginContext(ctx context.Context, login string) (*User, error) {
+	resp, err := ft.GetContext(ctx, "/users/"+login)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		switch resp.StatusCode {
-		case http.StatusNotFound:
-			return nil, errors.New("user not found")
-		default:
-			return nil, errors.New("failed getting user")
-		}
+		return nil, newAPIError(resp)
+	}
+	var user User
+	err = parseJSON(resp.Body, &user)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// GetUserByEmail gets a user by the provided email, the error matches ErrNotFound if there is none
+func (ft *API) GetUserByEmail(email string) (*User, error) {
+	return ft.GetUserByEmailContext(context.Background(), email)
+}
+
+// GetUserByEmailContext is the same as GetUserByEmail but uses ctx for the underlying requests
+func (ft *API) GetUserByEmailContext(ctx context.Context, email string) (*User, error) {
+	var users []*User
+	err := ft.PaginateContext(ctx, "/users", url.Values{"filter[email]": {email}}).All(&users)
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, fmt.Errorf("user with email %s: %w", email, ErrNotFound)
+	}
+	return users[0], nil
+}
+
+// GetMe gets the user owning the access token, it fails with the application's token
+func (ft *API) GetMe() (*User, error) {
+	return ft.GetMeContext(context.Background())
+}
+
+// GetMeContext is the same as GetMe but uses ctx for the underlying requests
+func (ft *API) GetMeContext(ctx context.Context) (*User, error) {
+	resp, err := ft.GetContext(ctx, "/me")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
 	}
 	var user User
 	err = parseJSON(resp.Body, &user)
@@ -280,8 +509,46 @@ func (ft *API) GetUserByLogin(login string) (*User, error) {
 	return &user, nil
 }
 
+// GetTokenInfo gets the scopes and owner of the access token
+func (ft *API) GetTokenInfo() (*TokenInfo, error) {
+	return ft.GetTokenInfoContext(context.Background())
+}
+
+// GetTokenInfoContext is the same as GetTokenInfo but uses ctx for the underlying requests
+func (ft *API) GetTokenInfoContext(ctx context.Context) (*TokenInfo, error) {
+	// The endpoint is not versioned, it lives next to the token endpoint
+	endpoint, err := url.Parse(ft.apiEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Path = "/oauth/token/info"
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ft.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+	var info TokenInfo
+	err = parseJSON(resp.Body, &info)
+	if err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
 // UpdateUser update a user's data
 func (ft *API) UpdateUser(login string, data *User) error {
+	return ft.UpdateUserContext(context.Background(), login, data)
+}
+
+// UpdateUserContext is the same as UpdateUser but uses ctx for the underlying requests
+func (ft *API) UpdateUserContext(ctx context.Context, login string, data *User) error {
 	payload := map[string]map[string]interface{}{
 		"user": {},
 	}
@@ -300,122 +567,114 @@ func (ft *API) UpdateUser(login string, data *User) error {
 	if data.Kind != "" {
 		payload["user"]["kind"] = data.Kind
 	}
-	resp, err := ft.PatchJSON("/users/"+login, payload)
+	resp, err := ft.PatchJSONContext(ctx, "/users/"+login, payload)
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode != http.StatusNoContent {
-		switch resp.StatusCode {
-		case http.StatusNotFound:
-			return errors.New("user not found")
-		default:
-			return errors.New("failed updating data")
-		}
+	defer resp.Body.Close()
+	if err := expectStatus(resp, http.StatusNoContent); err != nil {
+		return err
 	}
 	return nil
 }
 
 // AddCorrectionPoints add correction points to the provided user
 func (ft *API) AddCorrectionPoints(login string, points uint, reason string) error {
+	return ft.AddCorrectionPointsContext(context.Background(), login, points, reason)
+}
+
+// AddCorrectionPointsContext is the same as AddCorrectionPoints but uses ctx for the underlying requests
+func (ft *API) AddCorrectionPointsContext(ctx context.Context, login string, points uint, reason string) error {
 	payload := map[string]interface{}{
 		"reason": reason,
 		"amount": points,
 	}
-	resp, err := ft.PostJSON("/users/"+login+"/correction_points/add", payload)
+	resp, err := ft.PostJSONContext(ctx, "/users/"+login+"/correction_points/add", payload)
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode != http.StatusOK {
-		switch resp.StatusCode {
-		case http.StatusNotFound:
-			return errors.New("user not found")
-		default:
-			return errors.New("failed adding correction points")
-		}
+	defer resp.Body.Close()
+	if err := expectStatus(resp, http.StatusOK); err != nil {
+		return err
 	}
 	return nil
 }
 
 // RemoveCorrectionPoints remove correction points from the provided user
 func (ft *API) RemoveCorrectionPoints(login string, points uint, reason string) error {
+	return ft.RemoveCorrectionPointsContext(context.Background(), login, points, reason)
+}
+
+// RemoveCorrectionPointsContext is the same as RemoveCorrectionPoints but uses ctx for the underlying requests
+func (ft *API) RemoveCorrectionPointsContext(ctx context.Context, login string, points uint, reason string) error {
 	payload := map[string]interface{}{
 		"reason": reason,
 		"amount": points,
 	}
-	resp, err := ft.DeleteJSON("/users/"+login+"/correction_points/remove", payload)
+	resp, err := ft.DeleteJSONContext(ctx, "/users/"+login+"/correction_points/remove", payload)
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode != http.StatusOK {
-		switch resp.StatusCode {
-		case http.StatusNotFound:
-			return errors.New("user not found")
-		default:
-			return errors.New("failed removing correction points")
-		}
+	defer resp.Body.Close()
+	if err := expectStatus(resp, http.StatusOK); err != nil {
+		return err
 	}
 	return nil
 }
 
 // GetUserAgus get all AGUs for a user
 func (ft *API) GetUserAgus(login string) ([]Agu, error) {
-	resp, err := ft.Get("/users/" + login + "/anti_grav_units_users")
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		switch resp.StatusCode {
-		case http.StatusNotFound:
-			return nil, errors.New("user not found")
-		default:
-			return nil, errors.New("failed getting user")
-		}
-	}
+	return ft.GetUserAgusContext(context.Background(), login)
+}
+
+// GetUserAgusContext is the same as GetUserAgus but uses ctx for the underlying requests
+func (ft *API) GetUserAgusContext(ctx context.Context, login string) ([]Agu, error) {
 	var agus []Agu
-	err = parseJSON(resp.Body, &agus)
+	err := ft.PaginateContext(ctx, "/users/"+login+"/anti_grav_units_users", nil).PageSize(MaxPageSize).All(&agus)
 	if err != nil {
 		return nil, err
 	}
-	return agus, err
+	return agus, nil
 }
 
 // CreateFreePastAgu Create a free past AGU
 func (ft *API) CreateFreePastAgu(login string, duration int, reason string) error {
+	return ft.CreateFreePastAguContext(context.Background(), login, duration, reason)
+}
+
+// CreateFreePastAguContext is the same as CreateFreePastAgu but uses ctx for the underlying requests
+func (ft *API) CreateFreePastAguContext(ctx context.Context, login string, duration int, reason string) error {
 	payload := map[string]interface{}{
 		"duration": duration,
 	}
 	if reason != "" {
 		payload["reason"] = reason
 	}
-	resp, err := ft.PostJSON("/users/"+login+"/free_past_agu", payload)
+	resp, err := ft.PostJSONContext(ctx, "/users/"+login+"/free_past_agu", payload)
 	if err != nil {
 		return err
 	}
-	if resp.StatusCode != http.StatusOK {
-		switch resp.StatusCode {
-		case http.StatusNotFound:
-			return errors.New("user not found")
-		default:
-			return errors.New("failed adding free past agu")
-		}
+	defer resp.Body.Close()
+	if err := expectStatus(resp, http.StatusOK); err != nil {
+		return err
 	}
 	return nil
 }
 
+// GetProjectByName gets a project by its slug or id
 func (ft *API) GetProjectByName(name string) (*Project, error) {
-	resp, err := ft.Get("/projects/" + name)
+	return ft.GetProjectByNameContext(context.Background(), name)
+}
+
+// GetProjectByNameContext is the same as GetProjectByName but uses ctx for the underlying requests
+func (ft *API) GetProjectByNameContext(ctx context.Context, name string) (*Project, error) {
+	resp, err := ft.GetContext(ctx, "/projects/"+name)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		switch resp.StatusCode {
-		case http.StatusNotFound:
-			return nil, errors.New("user not found")
-		default:
-			return nil, errors.New("failed getting user")
-		}
+		return nil, newAPIError(resp)
 	}
 	var project Project
 	err = parseJSON(resp.Body, &project)
@@ -425,24 +684,35 @@ func (ft *API) GetProjectByName(name string) (*Project, error) {
 	return &project, nil
 }
 
+func listParams(filter_param map[string]string, range_param map[string]string) url.Values {
+	params := url.Values{}
+	for k, v := range filter_param {
+		params.Add("filter["+k+"]", v)
+	}
+	for k, v := range range_param {
+		params.Add("range["+k+"]", v)
+	}
+	return params
+}
+
+// GetUserProjects gets a single page of a user's projects_users
 func (ft *API) GetUserProjects(login string, filter_param map[string]string, range_param map[string]string, page_number int) ([]*ProjectUser, error) {
+	return ft.GetUserProjectsContext(context.Background(), login, filter_param, range_param, page_number)
+}
+
+// GetUserProjectsContext is the same as GetUserProjects but uses ctx for the underlying requests
+func (ft *API) GetUserProjectsContext(ctx context.Context, login string, filter_param map[string]string, range_param map[string]string, page_number int) ([]*ProjectUser, error) {
 	if login == "" {
 		return nil, errors.New("login not found")
 	}
-	req, err := http.NewRequest("GET", ft.apiEndpoint+"/users/"+login+"/projects_users", nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", ft.apiEndpoint+"/users/"+login+"/projects_users", nil)
 	if err != nil {
 		color.Set(color.FgRed)
 		log.Print("http.NewRequest:", err)
 		color.Set(color.Reset)
 		return nil, err
 	}
-	params := req.URL.Query()
-	for k, v := range filter_param {
-		params.Add("filter["+k+"]", v)
-	}
-	for k, v := range range_param {
-		params.Add("range["+k+"]", v)
-	}
+	params := listParams(filter_param, range_param)
 	if page_number > 0 {
 		str_num := strconv.Itoa(page_number)
 		params.Add("page[number]", str_num)
@@ -456,6 +726,9 @@ func (ft *API) GetUserProjects(login string, filter_param map[string]string, ran
 		return nil, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
 	var projects []*ProjectUser
 	err = parseJSON(resp.Body, &projects)
 	if err != nil {
@@ -468,3 +741,218 @@ func (ft *API) GetUserProjects(login string, filter_param map[string]string, ran
 	}
 	return projects, nil
 }
+
+// GetCampus gets a campus by its ID
+func (ft *API) GetCampus(id int) (*Campus, error) {
+	return ft.GetCampusContext(context.Background(), id)
+}
+
+// GetCampusContext is the same as GetCampus but uses ctx for the underlying requests
+func (ft *API) GetCampusContext(ctx context.Context, id int) (*Campus, error) {
+	resp, err := ft.GetContext(ctx, "/campus/"+strconv.Itoa(id))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+	var campus Campus
+	err = parseJSON(resp.Body, &campus)
+	if err != nil {
+		return nil, err
+	}
+	return &campus, nil
+}
+
+// ListCampuses returns a Pager over the campuses, params can filter or search them like search[name], items decode into a Campus
+func (ft *API) ListCampuses(params url.Values) *Pager {
+	return ft.ListCampusesContext(context.Background(), params)
+}
+
+// ListCampusesContext is the same as ListCampuses but uses ctx for the underlying requests
+func (ft *API) ListCampusesContext(ctx context.Context, params url.Values) *Pager {
+	return ft.PaginateContext(ctx, "/campus", params)
+}
+
+// ListCampusUsers returns a Pager over the users of a campus, params can filter them like filter[pool_year], items decode into a User
+func (ft *API) ListCampusUsers(campusID int, params url.Values) *Pager {
+	return ft.ListCampusUsersContext(context.Background(), campusID, params)
+}
+
+// ListCampusUsersContext is the same as ListCampusUsers but uses ctx for the underlying requests
+func (ft *API) ListCampusUsersContext(ctx context.Context, campusID int, params url.Values) *Pager {
+	return ft.PaginateContext(ctx, "/campus/"+strconv.Itoa(campusID)+"/users", params)
+}
+
+// ListCursusUsers returns a Pager over the users of a cursus, params can filter them like filter[primary_campus_id], items decode into a User
+func (ft *API) ListCursusUsers(cursusID int, params url.Values) *Pager {
+	return ft.ListCursusUsersContext(context.Background(), cursusID, params)
+}
+
+// ListCursusUsersContext is the same as ListCursusUsers but uses ctx for the underlying requests
+func (ft *API) ListCursusUsersContext(ctx context.Context, cursusID int, params url.Values) *Pager {
+	return ft.PaginateContext(ctx, "/cursus/"+strconv.Itoa(cursusID)+"/users", params)
+}
+
+// ListCampusCursusUsers returns a Pager over the enrollments in cursus of the users of a campus,
+// params can filter them like filter[cursus_id] or range[blackholed_at], items decode into a CursusUser
+func (ft *API) ListCampusCursusUsers(campusID int, params url.Values) *Pager {
+	return ft.ListCampusCursusUsersContext(context.Background(), campusID, params)
+}
+
+// ListCampusCursusUsersContext is the same as ListCampusCursusUsers but uses ctx for the underlying requests
+func (ft *API) ListCampusCursusUsersContext(ctx context.Context, campusID int, params url.Values) *Pager {
+	values := url.Values{}
+	for key, value := range params {
+		values[key] = value
+	}
+	values.Set("filter[campus_id]", strconv.Itoa(campusID))
+	return ft.PaginateContext(ctx, "/cursus_users", values)
+}
+
+// ListCampusLocations returns a Pager over the sessions on the computers of a campus,
+// params can filter them like filter[active] or filter[host], items decode into a Location
+func (ft *API) ListCampusLocations(campusID int, params url.Values) *Pager {
+	return ft.ListCampusLocationsContext(context.Background(), campusID, params)
+}
+
+// ListCampusLocationsContext is the same as ListCampusLocations but uses ctx for the underlying requests
+func (ft *API) ListCampusLocationsContext(ctx context.Context, campusID int, params url.Values) *Pager {
+	return ft.PaginateContext(ctx, "/campus/"+strconv.Itoa(campusID)+"/locations", params)
+}
+
+// ListUserLocations returns a Pager over the sessions of a user, params can filter them like range[begin_at], items decode into a Location
+func (ft *API) ListUserLocations(login string, params url.Values) *Pager {
+	return ft.ListUserLocationsContext(context.Background(), login, params)
+}
+
+// ListUserLocationsContext is the same as ListUserLocations but uses ctx for the underlying requests
+func (ft *API) ListUserLocationsContext(ctx context.Context, login string, params url.Values) *Pager {
+	return ft.PaginateContext(ctx, "/users/"+login+"/locations", params)
+}
+
+// ListCursus returns a Pager over the cursus, params can filter them like filter[slug], items decode into a Cursus
+func (ft *API) ListCursus(params url.Values) *Pager {
+	return ft.ListCursusContext(context.Background(), params)
+}
+
+// ListCursusContext is the same as ListCursus but uses ctx for the underlying requests
+func (ft *API) ListCursusContext(ctx context.Context, params url.Values) *Pager {
+	return ft.PaginateContext(ctx, "/cursus", params)
+}
+
+// GetUserCursus gets the cursus a user is enrolled in
+func (ft *API) GetUserCursus(login string) ([]*CursusUser, error) {
+	return ft.GetUserCursusContext(context.Background(), login)
+}
+
+// GetUserCursusContext is the same as GetUserCursus but uses ctx for the underlying requests
+func (ft *API) GetUserCursusContext(ctx context.Context, login string) ([]*CursusUser, error) {
+	var cursusUsers []*CursusUser
+	err := ft.PaginateContext(ctx, "/users/"+login+"/cursus_users", nil).All(&cursusUsers)
+	if err != nil {
+		return nil, err
+	}
+	return cursusUsers, nil
+}
+
+// GetCursusUser gets the enrollment of a user in a cursus by its ID
+func (ft *API) GetCursusUser(id int) (*CursusUser, error) {
+	return ft.GetCursusUserContext(context.Background(), id)
+}
+
+// GetCursusUserContext is the same as GetCursusUser but uses ctx for the underlying requests
+func (ft *API) GetCursusUserContext(ctx context.Context, id int) (*CursusUser, error) {
+	resp, err := ft.GetContext(ctx, "/cursus_users/"+strconv.Itoa(id))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, newAPIError(resp)
+	}
+	var cursusUser CursusUser
+	err = parseJSON(resp.Body, &cursusUser)
+	if err != nil {
+		return nil, err
+	}
+	return &cursusUser, nil
+}
+
+// AddUserToCursus enrolls the user in the cursus from beginAt, until endAt when it isn't nil
+func (ft *API) AddUserToCursus(userID int, cursusID int, beginAt time.Time, endAt *time.Time) (*CursusUser, error) {
+	return ft.AddUserToCursusContext(context.Background(), userID, cursusID, beginAt, endAt)
+}
+
+// AddUserToCursusContext is the same as AddUserToCursus but uses ctx for the underlying requests
+func (ft *API) AddUserToCursusContext(ctx context.Context, userID int, cursusID int, beginAt time.Time, endAt *time.Time) (*CursusUser, error) {
+	payload := map[string]map[string]interface{}{
+		"cursus_user": {
+			"user_id":   userID,
+			"cursus_id": cursusID,
+			"begin_at":  beginAt.Format(time.RFC3339),
+		},
+	}
+	if endAt != nil {
+		payload["cursus_user"]["end_at"] = endAt.Format(time.RFC3339)
+	}
+	resp, err := ft.PostJSONContext(ctx, "/cursus_users", payload)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := expectStatus(resp, http.StatusCreated); err != nil {
+		return nil, err
+	}
+	var cursusUser CursusUser
+	// Nothing was created in dry run mode, the enrollment is empty
+	if IsDryRun(resp) {
+		return &cursusUser, nil
+	}
+	err = parseJSON(resp.Body, &cursusUser)
+	if err != nil {
+		return nil, err
+	}
+	return &cursusUser, nil
+}
+
+// UpdateCursusUser updates the enrollment with the given ID, only the set EndAt, BlackholedAt and Grade are sent
+func (ft *API) UpdateCursusUser(id int, data *CursusUser) error {
+	return ft.UpdateCursusUserContext(context.Background(), id, data)
+}
+
+// UpdateCursusUserContext is the same as UpdateCursusUser but uses ctx for the underlying requests
+func (ft *API) UpdateCursusUserContext(ctx context.Context, id int, data *CursusUser) error {
+	payload := map[string]map[string]interface{}{
+		"cursus_user": {},
+	}
+	if data.EndAt != nil {
+		payload["cursus_user"]["end_at"] = data.EndAt.Format(time.RFC3339)
+	}
+	if data.BlackholedAt != nil {
+		payload["cursus_user"]["blackholed_at"] = data.BlackholedAt.Format(time.RFC3339)
+	}
+	if data.Grade != "" {
+		payload["cursus_user"]["grade"] = data.Grade
+	}
+	resp, err := ft.PatchJSONContext(ctx, "/cursus_users/"+strconv.Itoa(id), payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := expectStatus(resp, http.StatusNoContent); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ListUserProjects returns a Pager over all the projects_users of a user, items decode into a ProjectUser
+func (ft *API) ListUserProjects(login string, filter_param map[string]string, range_param map[string]string) *Pager {
+	return ft.ListUserProjectsContext(context.Background(), login, filter_param, range_param)
+}
+
+// ListUserProjectsContext is the same as ListUserProjects but uses ctx for the underlying requests
+func (ft *API) ListUserProjectsContext(ctx context.Context, login string, filter_param map[string]string, range_param map[string]string) *Pager {
+	return ft.PaginateContext(ctx, "/users/"+login+"/projects_users", listParams(filter_param, range_param))
+}
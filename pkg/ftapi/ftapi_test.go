@@ -2,12 +2,16 @@ package ftapi
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/oauth2/clientcredentials"
@@ -147,10 +151,24 @@ func TestHourlyLimit(t *testing.T) {
 	ftAPI := New(server.URL, server.Client())
 	resp, err := ftAPI.Get("/v2/users")
 	assert.NotNil(t, err)
-	assert.Equal(t, "exceeded rate limit", err.Error())
+	assert.True(t, errors.Is(err, ErrRateLimited))
+	assert.Equal(t, "GET /v2/users: 429 Too Many Requests", err.Error())
 	assert.Nil(t, resp)
 }
 
+func TestRetryAfterIsCancelledWithContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Add("Retry-After", "3600")
+		rw.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := ftAPI.GetUserByLoginContext(ctx, "spoody")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
 func TestCreateUser(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 		assert.Equal(t, "POST", req.Method)
@@ -220,7 +238,8 @@ func TestSetUserImageWithNotFound(t *testing.T) {
 	ftAPI := New(server.URL, server.Client())
 	err = ftAPI.SetUserImage("spoody", imgFile)
 	assert.NotNil(t, err)
-	assert.Equal(t, "user not found", err.Error())
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.Equal(t, "PATCH /users/spoody: 404 Not Found", err.Error())
 }
 
 func TestSetUserImageWithFailure(t *testing.T) {
@@ -236,7 +255,8 @@ func TestSetUserImageWithFailure(t *testing.T) {
 	ftAPI := New(server.URL, server.Client())
 	err = ftAPI.SetUserImage("spoody", imgFile)
 	assert.NotNil(t, err)
-	assert.Equal(t, "failed setting profile image", err.Error())
+	assert.False(t, errors.Is(err, ErrNotFound))
+	assert.Equal(t, "PATCH /users/spoody: 500 Internal Server Error", err.Error())
 }
 
 func TestCreateClose(t *testing.T) {
@@ -317,6 +337,64 @@ func TestGetUserByLogin(t *testing.T) {
 
 }
 
+func TestGetUserByEmail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "GET", req.Method)
+		assert.Equal(t, "/users", req.URL.Path)
+		assert.Equal(t, "mehdi@1337.ma", req.URL.Query().Get("filter[email]"))
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte(`[{"id":66356,"email":"mehdi@1337.ma","login":"spoody"}]`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	user, err := ftAPI.GetUserByEmail("mehdi@1337.ma")
+	assert.Nil(t, err)
+	assert.Equal(t, 66356, user.ID)
+	assert.Equal(t, "spoody", user.Login)
+}
+
+func TestGetUserByEmailNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+		_, _ = rw.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	user, err := ftAPI.GetUserByEmail("nobody@1337.ma")
+	assert.Nil(t, user)
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestGetMe(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "GET", req.Method)
+		assert.Equal(t, "/v2/me", req.URL.String())
+		_, _ = rw.Write([]byte(`{"id":66356,"login":"spoody","roles":[{"id":2,"name":"Events Manager"}]}`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL+"/v2", server.Client())
+	user, err := ftAPI.GetMe()
+	assert.Nil(t, err)
+	assert.Equal(t, "spoody", user.Login)
+	assert.Len(t, user.Roles, 1)
+	assert.Equal(t, "Events Manager", user.Roles[0].Name)
+}
+
+func TestGetTokenInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "GET", req.Method)
+		assert.Equal(t, "/oauth/token/info", req.URL.String())
+		_, _ = rw.Write([]byte(`{"resource_owner_id":66356,"scopes":["public","profile"],"expires_in_seconds":7174,"application":{"uid":"3089cd"},"created_at":1439460680}`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL+"/v2", server.Client())
+	info, err := ftAPI.GetTokenInfo()
+	assert.Nil(t, err)
+	assert.Equal(t, 66356, *info.ResourceOwnerID)
+	assert.Equal(t, []string{"public", "profile"}, info.Scopes)
+	assert.Equal(t, "3089cd", info.Application.UID)
+}
+
 func TestUpdateUser(t *testing.T) {
 	testData := []map[string]interface{}{
 		{
@@ -465,3 +543,149 @@ func TestGetProjectByName(t *testing.T) {
 	// TODO
 	// more test for ProjectSessions
 }
+
+func TestGetCampus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "GET", req.Method)
+		assert.Equal(t, "/v2/campus/21", req.URL.String())
+		_, _ = rw.Write([]byte(`{"id":21,"name":"Benguerir","time_zone":"Africa/Casablanca","country":"Morocco"}`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL+"/v2", server.Client())
+	campus, err := ftAPI.GetCampus(21)
+	assert.Nil(t, err)
+	assert.Equal(t, "Benguerir", campus.Name)
+	assert.Equal(t, "Africa/Casablanca", campus.TimeZone)
+}
+
+func TestListCampuses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/campus", req.URL.Path)
+		assert.Equal(t, "Morocco", req.URL.Query().Get("filter[country]"))
+		_, _ = rw.Write([]byte(`[{"id":16,"name":"Khouribga"},{"id":21,"name":"Benguerir"}]`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	var campuses []*Campus
+	err := ftAPI.ListCampuses(url.Values{"filter[country]": {"Morocco"}}).All(&campuses)
+	assert.Nil(t, err)
+	assert.Len(t, campuses, 2)
+	assert.Equal(t, "khouribga", campuses[0].Slug())
+}
+
+func TestSlugify(t *testing.T) {
+	for name, slug := range map[string]string{
+		"Paris":            "paris",
+		"São Paulo":        "sao-paulo",
+		"Khouribga":        "khouribga",
+		"42 Network - Ben": "42-network-ben",
+		" Québec  City ":   "quebec-city",
+	} {
+		assert.Equal(t, slug, Slugify(name), name)
+	}
+}
+
+func TestGetUserCursus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/users/spoody/cursus_users", req.URL.Path)
+		_, _ = rw.Write([]byte(`[{"id":7,"grade":"Learner","level":4.2,"cursus_id":21,"cursus":{"id":21,"slug":"42cursus"},"blackholed_at":"2022-01-01T00:00:00.000Z"}]`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	cursusUsers, err := ftAPI.GetUserCursus("spoody")
+	assert.Nil(t, err)
+	assert.Len(t, cursusUsers, 1)
+	assert.Equal(t, "42cursus", cursusUsers[0].Cursus.Slug)
+	assert.Equal(t, 2022, cursusUsers[0].BlackholedAt.Year())
+}
+
+func TestAddUserToCursus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "POST", req.Method)
+		assert.Equal(t, "/cursus_users", req.URL.String())
+		assert.Equal(t,
+			`{"cursus_user":{"begin_at":"2021-10-04T09:00:00Z","cursus_id":21,"end_at":"2023-10-04T09:00:00Z","user_id":37}}`,
+			getBody(req.Body),
+		)
+		rw.WriteHeader(http.StatusCreated)
+		_, _ = rw.Write([]byte(`{"id":7,"cursus_id":21,"user":{"id":37,"login":"spoody"}}`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	endAt := time.Date(2023, 10, 4, 9, 0, 0, 0, time.UTC)
+	cursusUser, err := ftAPI.AddUserToCursus(37, 21, time.Date(2021, 10, 4, 9, 0, 0, 0, time.UTC), &endAt)
+	assert.Nil(t, err)
+	assert.Equal(t, 7, cursusUser.ID)
+	assert.Equal(t, "spoody", cursusUser.User.Login)
+}
+
+func TestAddUserToCursusInvalidResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusCreated)
+		_, _ = rw.Write([]byte(`<html>Created</html>`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	_, err := ftAPI.AddUserToCursus(37, 21, time.Date(2021, 10, 4, 9, 0, 0, 0, time.UTC), nil)
+	assert.NotNil(t, err)
+
+	var dryRun bytes.Buffer
+	ftAPI = New(server.URL, server.Client(), WithDryRun(&dryRun))
+	cursusUser, err := ftAPI.AddUserToCursus(37, 21, time.Date(2021, 10, 4, 9, 0, 0, 0, time.UTC), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, cursusUser.ID)
+	assert.Contains(t, dryRun.String(), "POST "+server.URL+"/cursus_users\n")
+}
+
+func TestUpdateCursusUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "PATCH", req.Method)
+		assert.Equal(t, "/cursus_users/7", req.URL.String())
+		assert.Equal(t, `{"cursus_user":{"end_at":"2023-10-04T09:00:00Z","grade":"Member"}}`, getBody(req.Body))
+		rw.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	endAt := time.Date(2023, 10, 4, 9, 0, 0, 0, time.UTC)
+	assert.Nil(t, ftAPI.UpdateCursusUser(7, &CursusUser{EndAt: &endAt, Grade: "Member"}))
+}
+
+func TestListCampusCursusUsers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "/cursus_users", req.URL.Path)
+		assert.Equal(t, "21", req.URL.Query().Get("filter[campus_id]"))
+		assert.Equal(t, "42", req.URL.Query().Get("filter[cursus_id]"))
+		_, _ = rw.Write([]byte(`[{"id":7,"cursus_id":42,"user":{"login":"spoody"}}]`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	params := url.Values{"filter[cursus_id]": {"42"}}
+	var cursusUsers []*CursusUser
+	assert.Nil(t, ftAPI.ListCampusCursusUsers(21, params).All(&cursusUsers))
+	assert.Equal(t, "spoody", cursusUsers[0].User.Login)
+	assert.Empty(t, params.Get("filter[campus_id]"))
+}
+
+func TestListLocations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/campus/21/locations":
+			assert.Equal(t, "true", req.URL.Query().Get("filter[active]"))
+			_, _ = rw.Write([]byte(`[{"id":1,"host":"e1r2p3","begin_at":"2021-11-02T08:00:00Z","user":{"login":"spoody"}}]`))
+		case "/users/spoody/locations":
+			_, _ = rw.Write([]byte(`[{"id":1,"host":"e1r2p3","begin_at":"2021-11-02T08:00:00Z","end_at":"2021-11-02T10:30:00Z"}]`))
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	var locations []*Location
+	assert.Nil(t, ftAPI.ListCampusLocations(21, url.Values{"filter[active]": {"true"}}).All(&locations))
+	assert.Equal(t, "spoody", locations[0].User.Login)
+	assert.Nil(t, locations[0].EndAt)
+
+	locations = nil
+	assert.Nil(t, ftAPI.ListUserLocations("spoody", nil).All(&locations))
+	assert.Equal(t, 150*time.Minute, locations[0].Duration(time.Now()))
+}
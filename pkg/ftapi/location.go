@@ -0,0 +1,25 @@
+package ftapi
+
+import "time"
+
+// Location represents a session of a user on a campus computer, EndAt is nil while it is active
+type Location struct {
+	ID int `json:"id,omitempty"`
+	BeginAt time.Time `json:"begin_at,omitempty"`
+	EndAt *time.Time `json:"end_at,omitempty"`
+	Primary bool `json:"primary,omitempty"`
+	Floor string `json:"floor,omitempty"`
+	Row string `json:"row,omitempty"`
+	Post string `json:"post,omitempty"`
+	Host string `json:"host,omitempty"`
+	CampusID int `json:"campus_id,omitempty"`
+	User *User `json:"user,omitempty"`
+}
+
+// Duration returns how long the session lasted, until now when it is active
+func (l *Location) Duration(now time.Time) time.Duration {
+	if l.EndAt == nil {
+		return now.Sub(l.BeginAt)
+	}
+	return l.EndAt.Sub(l.BeginAt)
+}
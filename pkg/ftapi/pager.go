@@ -0,0 +1,200 @@
+package ftapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+)
+
+const (
+	// DefaultPageSize is the page size used by a Pager unless told otherwise
+	DefaultPageSize = 30
+	// MaxPageSize is the biggest page[size] accepted by the 42 API
+	MaxPageSize = 100
+)
+
+var linkNextRegexp = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// Pager lazily iterates over the items of a paginated list endpoint.
+// Pages are only fetched when the items of the previous one have been consumed:
+//
+//	pager := api.Paginate("/users/spoody/projects_users", nil)
+//	for pager.Next() {
+//		var project ProjectUser
+//		if err := pager.Decode(&project); err != nil {
+//			return err
+//		}
+//	}
+//	if err := pager.Err(); err != nil {
+//		return err
+//	}
+type Pager struct {
+	ctx      context.Context
+	api      *API
+	url      string
+	params   url.Values
+	pageSize int
+
+	nextURL string
+	page    int
+	total   int
+	done    bool
+	items   []json.RawMessage
+	current json.RawMessage
+	err     error
+}
+
+// Paginate returns a Pager over the list endpoint at url, params are added to every page request
+func (ft *API) Paginate(url string, params url.Values) *Pager {
+	return ft.PaginateContext(context.Background(), url, params)
+}
+
+// PaginateContext is the same as Paginate but every page is requested with ctx,
+// cancelling it stops the iteration and makes Err return ctx's error
+func (ft *API) PaginateContext(ctx context.Context, url string, params url.Values) *Pager {
+	return &Pager{
+		ctx:      ctx,
+		api:      ft,
+		url:      url,
+		params:   params,
+		pageSize: DefaultPageSize,
+		total:    -1,
+	}
+}
+
+// PageSize sets the number of items requested per page, it is capped to MaxPageSize
+// and must be set before the first call to Next
+func (p *Pager) PageSize(size int) *Pager {
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+	if size > MaxPageSize {
+		size = MaxPageSize
+	}
+	p.pageSize = size
+	return p
+}
+
+// Next advances the pager to the next item, fetching a new page if needed.
+// It returns false when there are no more items or when an error occurred, see Err
+func (p *Pager) Next() bool {
+	if p.err != nil {
+		return false
+	}
+	for len(p.items) == 0 {
+		if p.done {
+			p.current = nil
+			return false
+		}
+		if err := p.fetch(); err != nil {
+			p.err = err
+			return false
+		}
+	}
+	p.current = p.items[0]
+	p.items = p.items[1:]
+	return true
+}
+
+// Decode unmarshals the current item into target
+func (p *Pager) Decode(target interface{}) error {
+	return json.Unmarshal(p.current, target)
+}
+
+// Err returns the first error encountered while fetching pages
+func (p *Pager) Err() error {
+	return p.err
+}
+
+// Total returns the total number of items as announced by the API, or -1 if unknown
+func (p *Pager) Total() int {
+	return p.total
+}
+
+// All fetches the remaining items and decodes them into target, which must be a pointer to a slice
+func (p *Pager) All(target interface{}) error {
+	var items []json.RawMessage
+	for p.Next() {
+		items = append(items, p.current)
+	}
+	if p.err != nil {
+		return p.err
+	}
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, target)
+}
+
+func (p *Pager) fetch() error {
+	req, err := p.nextRequest()
+	if err != nil {
+		return err
+	}
+	resp, err := p.api.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return newAPIError(resp)
+	}
+	var items []json.RawMessage
+	err = parseJSON(resp.Body, &items)
+	if err != nil {
+		return err
+	}
+	p.items = items
+	p.page++
+	p.updateFromHeaders(resp.Header, len(items))
+	return nil
+}
+
+func (p *Pager) nextRequest() (*http.Request, error) {
+	if p.nextURL != "" {
+		return http.NewRequestWithContext(p.ctx, "GET", p.nextURL, nil)
+	}
+	req, err := http.NewRequestWithContext(p.ctx, "GET", p.api.apiEndpoint+p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	params := req.URL.Query()
+	for k, values := range p.params {
+		for _, v := range values {
+			params.Add(k, v)
+		}
+	}
+	params.Set("page[number]", strconv.Itoa(p.page+1))
+	params.Set("page[size]", strconv.Itoa(p.pageSize))
+	req.URL.RawQuery = params.Encode()
+	return req, nil
+}
+
+// updateFromHeaders decides whether there is a next page, preferring the Link header
+// and falling back on X-Total/X-Per-Page and finally on the size of the page
+func (p *Pager) updateFromHeaders(header http.Header, count int) {
+	if total, err := strconv.Atoi(header.Get("X-Total")); err == nil {
+		p.total = total
+	}
+	if link := header.Get("Link"); link != "" {
+		p.nextURL = ""
+		if match := linkNextRegexp.FindStringSubmatch(link); match != nil {
+			p.nextURL = match[1]
+		}
+		p.done = p.nextURL == ""
+		return
+	}
+	perPage, err := strconv.Atoi(header.Get("X-Per-Page"))
+	if err != nil || perPage <= 0 {
+		perPage = p.pageSize
+	}
+	if p.total >= 0 {
+		p.done = p.page*perPage >= p.total
+		return
+	}
+	p.done = count == 0 || count < perPage
+}
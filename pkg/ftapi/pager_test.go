@@ -0,0 +1,93 @@
+package ftapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPagerFollowsLinkHeader(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "GET", req.Method)
+		assert.Equal(t, "/users/spoody/anti_grav_units_users", req.URL.Path)
+		page, _ := strconv.Atoi(req.URL.Query().Get("page[number]"))
+		if page < 2 {
+			rw.Header().Set("Link", fmt.Sprintf(`<%s%s?page[number]=%d&page[size]=2>; rel="next"`, server.URL, req.URL.Path, page+1))
+		}
+		rw.Header().Set("X-Total", "3")
+		if page == 1 {
+			_, _ = rw.Write([]byte(`[{"id":1},{"id":2}]`))
+			return
+		}
+		_, _ = rw.Write([]byte(`[{"id":3}]`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	pager := ftAPI.Paginate("/users/spoody/anti_grav_units_users", nil).PageSize(2)
+	var ids []int
+	for pager.Next() {
+		var agu Agu
+		assert.Nil(t, pager.Decode(&agu))
+		ids = append(ids, agu.ID)
+	}
+	assert.Nil(t, pager.Err())
+	assert.Equal(t, []int{1, 2, 3}, ids)
+	assert.Equal(t, 3, pager.Total())
+}
+
+func TestPagerWithoutLinkHeader(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		assert.Equal(t, "100", req.URL.Query().Get("page[size]"))
+		assert.Equal(t, "spoody", req.URL.Query().Get("filter[login]"))
+		rw.Header().Set("X-Per-Page", "100")
+		if req.URL.Query().Get("page[number]") == "1" {
+			_, _ = rw.Write([]byte(`[{"id":1},{"id":2}]`))
+			return
+		}
+		_, _ = rw.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	var agus []Agu
+	err := ftAPI.Paginate("/users", map[string][]string{"filter[login]": {"spoody"}}).PageSize(500).All(&agus)
+	assert.Nil(t, err)
+	assert.Len(t, agus, 2)
+	assert.Equal(t, 1, requests)
+}
+
+func TestPagerIsLazy(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		rw.Header().Set("X-Total", "40")
+		rw.Header().Set("X-Per-Page", "2")
+		_, _ = rw.Write([]byte(`[{"id":1},{"id":2}]`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	pager := ftAPI.Paginate("/users", nil).PageSize(2)
+	assert.True(t, pager.Next())
+	assert.True(t, pager.Next())
+	assert.Equal(t, 1, requests)
+	assert.True(t, pager.Next())
+	assert.Equal(t, 2, requests)
+}
+
+func TestPagerWithFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	pager := ftAPI.Paginate("/users", nil)
+	assert.False(t, pager.Next())
+	assert.True(t, errors.Is(pager.Err(), ErrNotFound))
+}
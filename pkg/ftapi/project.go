@@ -56,7 +56,7 @@ type Project struct {
 	Exam            bool             `json:"exam"`
 	GitID           *int             `json:"git_id,omitempty"`
 	Repogitory      *string          `json:"repository,omitempty"`
-	Cursus          []*cursus        `json:"cursus,omitempty"`
+	Cursus          []*Cursus        `json:"cursus,omitempty"`
 	Campus          []*Campus        `json:"campus,omitempty"`
 	Videos          []string         `json:"videos,omitempty"` // TODO
 	ProjectSessions []*session       `json:"project_sessions,omitempty"`
@@ -84,14 +84,15 @@ type Team struct {
 }
 
 type ProjectUser struct {
-	ID            int     `json:"id,omitempty"`
-	Occurrence    int     `json:"occurrence,omitempty"`
-	FinalMark     int     `json:"final_mark,omitempty"`
-	Status        string  `json:"status,omitempty"`
-	Validated     bool    `json:"validated?,omitempty"`
-	CurrentTeamID int     `json:"current_team_id,omitempty"`
-	Project       Project `json:"project,omitempty"`
-	CursusIDs     []int   `json:"cursus_ids,omitempty"`
-	User          User    `json:"user,omitempty"`
-	Teams         []Team  `json:"teams,omitempty"`
+	ID            int        `json:"id,omitempty"`
+	Occurrence    int        `json:"occurrence,omitempty"`
+	FinalMark     int        `json:"final_mark,omitempty"`
+	Status        string     `json:"status,omitempty"`
+	Validated     bool       `json:"validated?,omitempty"`
+	MarkedAt      *time.Time `json:"marked_at,omitempty"`
+	CurrentTeamID int        `json:"current_team_id,omitempty"`
+	Project       Project    `json:"project,omitempty"`
+	CursusIDs     []int      `json:"cursus_ids,omitempty"`
+	User          User       `json:"user,omitempty"`
+	Teams         []Team     `json:"teams,omitempty"`
 }
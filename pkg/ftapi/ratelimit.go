@@ -0,0 +1,162 @@
+package ftapi
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSecondlyLimit is the number of requests per second allowed to a 42 application by default
+	DefaultSecondlyLimit = 2
+	// DefaultHourlyLimit is the number of requests per hour allowed to a 42 application by default
+	DefaultHourlyLimit = 1200
+)
+
+// RateLimit is a snapshot of the request budget of a RateLimiter
+type RateLimit struct {
+	SecondlyLimit     int
+	SecondlyRemaining int
+	HourlyLimit       int
+	HourlyRemaining   int
+	// HourlyReset is when the hourly budget is expected to be refilled, zero if it isn't exhausted
+	HourlyReset time.Time
+}
+
+// RateLimiter is a token bucket that proactively delays requests to stay within the 42 API quotas.
+// Its limits are tuned from the X-Secondly-RateLimit-* and X-Hourly-RateLimit-* response headers.
+// A RateLimiter is safe for concurrent use and can be shared by several API instances
+type RateLimiter struct {
+	mu sync.Mutex
+
+	secondlyLimit int
+	tokens        float64
+	lastRefill    time.Time
+
+	hourlyLimit     int
+	hourlyRemaining int
+	hourlyReset     time.Time
+
+	now func() time.Time
+}
+
+// NewRateLimiter creates a RateLimiter allowing perSecond requests per second and perHour requests per hour
+func NewRateLimiter(perSecond int, perHour int) *RateLimiter {
+	if perSecond <= 0 {
+		perSecond = DefaultSecondlyLimit
+	}
+	if perHour <= 0 {
+		perHour = DefaultHourlyLimit
+	}
+	l := &RateLimiter{
+		secondlyLimit:   perSecond,
+		tokens:          float64(perSecond),
+		hourlyLimit:     perHour,
+		hourlyRemaining: perHour,
+		now:             time.Now,
+	}
+	l.lastRefill = l.now()
+	return l
+}
+
+// Wait blocks until a request can be sent or ctx is done, and consumes a token from the budget
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay := l.reserve()
+		if delay <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available, otherwise it returns how long to wait before trying again
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.refill(now)
+	if l.hourlyRemaining <= 0 {
+		return l.hourlyReset.Sub(now)
+	}
+	if l.tokens < 1 {
+		missing := 1 - l.tokens
+		return time.Duration(math.Ceil(missing / float64(l.secondlyLimit) * float64(time.Second)))
+	}
+	l.tokens--
+	l.hourlyRemaining--
+	if l.hourlyRemaining <= 0 {
+		l.hourlyReset = now.Truncate(time.Hour).Add(time.Hour)
+	}
+	return 0
+}
+
+func (l *RateLimiter) refill(now time.Time) {
+	elapsed := now.Sub(l.lastRefill).Seconds()
+	l.lastRefill = now
+	if elapsed > 0 {
+		l.tokens = math.Min(float64(l.secondlyLimit), l.tokens+elapsed*float64(l.secondlyLimit))
+	}
+	if l.hourlyRemaining <= 0 && !now.Before(l.hourlyReset) {
+		l.hourlyRemaining = l.hourlyLimit
+		l.hourlyReset = time.Time{}
+	}
+}
+
+// Update tunes the limiter using the rate limit headers of a response
+func (l *RateLimiter) Update(header http.Header) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.refill(now)
+	if limit, ok := headerInt(header, "X-Secondly-RateLimit-Limit"); ok && limit > 0 {
+		l.secondlyLimit = limit
+	}
+	if remaining, ok := headerInt(header, "X-Secondly-RateLimit-Remaining"); ok {
+		l.tokens = math.Min(l.tokens, float64(remaining))
+	}
+	if limit, ok := headerInt(header, "X-Hourly-RateLimit-Limit"); ok && limit > 0 {
+		l.hourlyLimit = limit
+	}
+	if remaining, ok := headerInt(header, "X-Hourly-RateLimit-Remaining"); ok {
+		l.hourlyRemaining = remaining
+		if remaining <= 0 && l.hourlyReset.IsZero() {
+			l.hourlyReset = now.Truncate(time.Hour).Add(time.Hour)
+		}
+	}
+}
+
+// Status returns the current budget
+func (l *RateLimiter) Status() RateLimit {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(l.now())
+	return RateLimit{
+		SecondlyLimit:     l.secondlyLimit,
+		SecondlyRemaining: int(l.tokens),
+		HourlyLimit:       l.hourlyLimit,
+		HourlyRemaining:   l.hourlyRemaining,
+		HourlyReset:       l.hourlyReset,
+	}
+}
+
+func headerInt(header http.Header, key string) (int, bool) {
+	value := header.Get(key)
+	if value == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
@@ -0,0 +1,105 @@
+package ftapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestLimiter(perSecond int, perHour int) (*RateLimiter, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2021, 11, 5, 14, 30, 0, 0, time.UTC)}
+	l := NewRateLimiter(perSecond, perHour)
+	l.now = clock.Now
+	l.lastRefill = clock.now
+	return l, clock
+}
+
+func TestRateLimiterSecondlyBudget(t *testing.T) {
+	l, clock := newTestLimiter(2, 100)
+	assert.Equal(t, time.Duration(0), l.reserve())
+	assert.Equal(t, time.Duration(0), l.reserve())
+	assert.Equal(t, 500*time.Millisecond, l.reserve())
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	assert.Equal(t, time.Duration(0), l.reserve())
+
+	status := l.Status()
+	assert.Equal(t, 2, status.SecondlyLimit)
+	assert.Equal(t, 0, status.SecondlyRemaining)
+	assert.Equal(t, 97, status.HourlyRemaining)
+}
+
+func TestRateLimiterHourlyBudget(t *testing.T) {
+	l, clock := newTestLimiter(10, 1)
+	assert.Equal(t, time.Duration(0), l.reserve())
+	assert.Equal(t, 30*time.Minute, l.reserve())
+	assert.Equal(t, time.Date(2021, 11, 5, 15, 0, 0, 0, time.UTC), l.Status().HourlyReset)
+
+	clock.now = clock.now.Add(30 * time.Minute)
+	assert.Equal(t, time.Duration(0), l.reserve())
+}
+
+func TestRateLimiterUpdate(t *testing.T) {
+	l, _ := newTestLimiter(2, 1200)
+	header := http.Header{}
+	header.Set("X-Secondly-RateLimit-Limit", "8")
+	header.Set("X-Secondly-RateLimit-Remaining", "0")
+	header.Set("X-Hourly-RateLimit-Limit", "3600")
+	header.Set("X-Hourly-RateLimit-Remaining", "3000")
+	l.Update(header)
+
+	status := l.Status()
+	assert.Equal(t, 8, status.SecondlyLimit)
+	assert.Equal(t, 0, status.SecondlyRemaining)
+	assert.Equal(t, 3600, status.HourlyLimit)
+	assert.Equal(t, 3000, status.HourlyRemaining)
+	assert.Equal(t, 125*time.Millisecond, l.reserve())
+}
+
+func TestRateLimiterWaitIsCancelled(t *testing.T) {
+	l, _ := newTestLimiter(1, 1)
+	assert.Nil(t, l.Wait(context.Background()))
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, l.Wait(ctx))
+}
+
+func TestRateLimiterIsShared(t *testing.T) {
+	l := NewRateLimiter(1000, 50)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, l.Wait(context.Background()))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 0, l.Status().HourlyRemaining)
+}
+
+func TestAPITunesRateLimiterFromHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("X-Hourly-RateLimit-Limit", "1200")
+		rw.Header().Set("X-Hourly-RateLimit-Remaining", "1100")
+		_, _ = rw.Write([]byte(`OK`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client())
+	_, err := ftAPI.Get("/users")
+	assert.Nil(t, err)
+	assert.Equal(t, 1100, ftAPI.RateLimit().HourlyRemaining)
+}
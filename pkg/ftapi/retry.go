@@ -0,0 +1,113 @@
+package ftapi
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy defines how requests failing with a network error or a 5xx status are retried
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts including the first one, 1 disables retries
+	MaxAttempts int
+	// MaxElapsedTime stops retrying once the request has been running for that long, 0 means no limit
+	MaxElapsedTime time.Duration
+	// InitialInterval is the delay before the first retry, it doubles on each retry
+	InitialInterval time.Duration
+	// MaxInterval caps the delay between two retries
+	MaxInterval time.Duration
+	// RetryNonIdempotent also retries POST, PATCH and DELETE requests,
+	// their body is rewound using http.Request.GetBody
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy is used by API instances unless WithRetryPolicy is given
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:     4,
+	MaxElapsedTime:  30 * time.Second,
+	InitialInterval: 500 * time.Millisecond,
+	MaxInterval:     8 * time.Second,
+}
+
+// WithRetryPolicy sets the retry policy used for transient failures
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(ft *API) {
+		ft.retryPolicy = policy
+	}
+}
+
+// shouldRetry reports whether a request that got resp or err on its attempt-th try should be sent again
+func (p RetryPolicy) shouldRetry(req *http.Request, resp *http.Response, err error, attempt int) bool {
+	if attempt >= p.MaxAttempts || req.Context().Err() != nil {
+		return false
+	}
+	if !isIdempotent(req.Method) && !p.RetryNonIdempotent {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// The body can't be rewound
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns the delay before the retry following the attempt-th try,
+// an exponential backoff with jitter between half and all of the interval
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	interval := p.InitialInterval
+	for i := 1; i < attempt && interval < p.MaxInterval; i++ {
+		interval *= 2
+	}
+	if p.MaxInterval > 0 && interval > p.MaxInterval {
+		interval = p.MaxInterval
+	}
+	if interval <= 0 {
+		return 0
+	}
+	half := interval / 2
+	return half + time.Duration(rand.Int63n(int64(interval-half)+1))
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+	return false
+}
+
+// rewindBody resets the body of req so it can be sent again
+func rewindBody(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,102 @@
+package ftapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts:     3,
+	InitialInterval: time.Millisecond,
+	MaxInterval:     5 * time.Millisecond,
+}
+
+func TestRetryOnBadGateway(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		if requests == 1 {
+			rw.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = rw.Write([]byte(`OK`))
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client(), WithRetryPolicy(testRetryPolicy), WithRateLimiter(nil))
+	resp, err := ftAPI.Get("/users")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "OK", getBody(resp.Body))
+	assert.Equal(t, 2, requests)
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client(), WithRetryPolicy(testRetryPolicy), WithRateLimiter(nil))
+	resp, err := ftAPI.Get("/users")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, 3, requests)
+}
+
+func TestNoRetryForNonIdempotentRequests(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		rw.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+	ftAPI := New(server.URL, server.Client(), WithRetryPolicy(testRetryPolicy), WithRateLimiter(nil))
+	err := ftAPI.AddCorrectionPoints("spoody", 5, "Testing")
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, requests)
+}
+
+func TestRetryNonIdempotentRewindsBody(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		requests++
+		assert.Equal(t, "{\"amount\":5,\"reason\":\"Testing\"}", getBody(req.Body))
+		if requests == 1 {
+			rw.WriteHeader(http.StatusGatewayTimeout)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	policy := testRetryPolicy
+	policy.RetryNonIdempotent = true
+	ftAPI := New(server.URL, server.Client(), WithRetryPolicy(policy), WithRateLimiter(nil))
+	err := ftAPI.AddCorrectionPoints("spoody", 5, "Testing")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, requests)
+}
+
+func TestRetryOnNetworkError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	url := server.URL
+	server.Close()
+	ftAPI := New(url, &http.Client{}, WithRetryPolicy(testRetryPolicy), WithRateLimiter(nil))
+	start := time.Now()
+	_, err := ftAPI.Get("/users")
+	assert.NotNil(t, err)
+	assert.True(t, time.Since(start) >= time.Millisecond)
+}
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialInterval: 100 * time.Millisecond, MaxInterval: time.Second}
+	for attempt, max := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 10: time.Second} {
+		delay := policy.backoff(attempt)
+		assert.True(t, delay >= max/2, "attempt %d: %s", attempt, delay)
+		assert.True(t, delay <= max, "attempt %d: %s", attempt, delay)
+	}
+}
@@ -0,0 +1,225 @@
+package ftapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/oauth2"
+)
+
+// TokenCache stores an OAuth2 token in a file only readable by the current user
+type TokenCache struct {
+	Path string
+}
+
+// NewTokenCache creates a TokenCache backed by the file at path
+func NewTokenCache(path string) *TokenCache {
+	return &TokenCache{Path: path}
+}
+
+// Load reads the cached token, it returns nil without error if nothing is cached
+func (c *TokenCache) Load() (*oauth2.Token, error) {
+	data, err := ioutil.ReadFile(c.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var token oauth2.Token
+	err = json.Unmarshal(data, &token)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// Save writes token to the cache with 0600 permissions
+func (c *TokenCache) Save(token *oauth2.Token) error {
+	data, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(c.Path), 0700)
+	if err != nil {
+		return err
+	}
+	file, err := ioutil.TempFile(filepath.Dir(c.Path), filepath.Base(c.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(file.Name(), 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), c.Path)
+}
+
+// Clear removes the cached token
+func (c *TokenCache) Clear() error {
+	err := os.Remove(c.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// TokenInfo describes an access token, as returned by /oauth/token/info
+type TokenInfo struct {
+	// ResourceOwnerID is the ID of the user owning the token, nil for the application's token
+	ResourceOwnerID  *int     `json:"resource_owner_id"`
+	Scopes           []string `json:"scopes"`
+	ExpiresInSeconds int      `json:"expires_in_seconds"`
+	Application      struct {
+		UID string `json:"uid"`
+	} `json:"application"`
+	CreatedAt int64 `json:"created_at"`
+}
+
+// TokenFetcher gets a new token, current is the token being replaced and may be nil.
+// ctx is the context of the request needing the token
+type TokenFetcher func(ctx context.Context, current *oauth2.Token) (*oauth2.Token, error)
+
+// CachedTokenSource is an oauth2.TokenSource that reuses the token stored in a TokenCache
+// until it expires, new tokens are fetched with a TokenFetcher and saved back to the cache
+type CachedTokenSource struct {
+	mu    sync.Mutex
+	cache *TokenCache
+	fetch TokenFetcher
+	token *oauth2.Token
+}
+
+// NewCachedTokenSource creates a CachedTokenSource
+func NewCachedTokenSource(cache *TokenCache, fetch TokenFetcher) *CachedTokenSource {
+	return &CachedTokenSource{
+		cache: cache,
+		fetch: fetch,
+	}
+}
+
+// Token returns a valid token, from memory, from the cache or freshly fetched
+func (s *CachedTokenSource) Token() (*oauth2.Token, error) {
+	return s.TokenContext(context.Background())
+}
+
+// TokenContext is the same as Token but uses ctx to fetch a new token
+func (s *CachedTokenSource) TokenContext(ctx context.Context) (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.Valid() {
+		return s.token, nil
+	}
+	if s.token == nil {
+		// A corrupted cache is not fatal, we just fetch a new token
+		s.token, _ = s.cache.Load()
+		if s.token.Valid() {
+			return s.token, nil
+		}
+	}
+	return s.refresh(ctx)
+}
+
+// Cached reports whether a token is available, in memory or in the cache, even if it is expired
+func (s *CachedTokenSource) Cached() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == nil {
+		s.token, _ = s.cache.Load()
+	}
+	return s.token != nil
+}
+
+// Refresh fetches a new token even if the current one is still valid
+func (s *CachedTokenSource) Refresh() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == nil {
+		s.token, _ = s.cache.Load()
+	}
+	return s.refresh(context.Background())
+}
+
+// Store replaces the current token and saves it to the cache
+func (s *CachedTokenSource) Store(token *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+	return s.cache.Save(token)
+}
+
+// Clear forgets the current token and removes it from the cache
+func (s *CachedTokenSource) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = nil
+	return s.cache.Clear()
+}
+
+func (s *CachedTokenSource) refresh(ctx context.Context) (*oauth2.Token, error) {
+	token, err := s.fetch(ctx, s.token)
+	if err != nil {
+		return nil, err
+	}
+	s.token = token
+	err = s.cache.Save(token)
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// contextTokenSource is a token source which can fetch its tokens with the context of the request, like CachedTokenSource
+type contextTokenSource interface {
+	TokenContext(ctx context.Context) (*oauth2.Token, error)
+}
+
+// tokenTransport authorizes the requests with the tokens of source, unlike oauth2.Transport
+// the tokens are fetched with the context of the request so its deadline and cancellation apply
+type tokenTransport struct {
+	source oauth2.TokenSource
+	base   http.RoundTripper
+}
+
+// RoundTrip sends req with the Authorization header of the current token
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var token *oauth2.Token
+	var err error
+	if source, ok := t.source.(contextTokenSource); ok {
+		token, err = source.TokenContext(req.Context())
+	} else {
+		token, err = t.source.Token()
+	}
+	if err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, err
+	}
+	authorized := req.Clone(req.Context())
+	token.SetAuthHeader(authorized)
+	return t.base.RoundTrip(authorized)
+}
+
+// NewFromTokenSource Creates an API instance authenticated with the tokens of tokenSource,
+// the HTTP client of ctx is used to send the requests when it has one, see oauth2.HTTPClient
+func NewFromTokenSource(ctx context.Context, apiEndpoint string, tokenSource oauth2.TokenSource, options ...Option) APIInterface {
+	base := oauth2.NewClient(ctx, nil).Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return New(apiEndpoint, &http.Client{Transport: &tokenTransport{source: tokenSource, base: base}}, options...)
+}
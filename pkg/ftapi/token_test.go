@@ -0,0 +1,128 @@
+package ftapi
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/oauth2"
+)
+
+func tempTokenCache(t *testing.T) *TokenCache {
+	dir, err := ioutil.TempDir("", "goft")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+	return NewTokenCache(filepath.Join(dir, "goft", "token.json"))
+}
+
+func TestTokenCache(t *testing.T) {
+	cache := tempTokenCache(t)
+	token, err := cache.Load()
+	assert.Nil(t, err)
+	assert.Nil(t, token)
+
+	expiry := time.Now().Add(time.Hour).Round(time.Second)
+	err = cache.Save(&oauth2.Token{AccessToken: "access", TokenType: "bearer", Expiry: expiry})
+	assert.Nil(t, err)
+	stat, err := os.Stat(cache.Path)
+	assert.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), stat.Mode().Perm())
+
+	token, err = cache.Load()
+	assert.Nil(t, err)
+	assert.Equal(t, "access", token.AccessToken)
+	assert.True(t, expiry.Equal(token.Expiry))
+
+	assert.Nil(t, cache.Clear())
+	assert.Nil(t, cache.Clear())
+	token, err = cache.Load()
+	assert.Nil(t, err)
+	assert.Nil(t, token)
+}
+
+func TestCachedTokenSourceReusesCachedToken(t *testing.T) {
+	cache := tempTokenCache(t)
+	fetched := 0
+	fetch := func(ctx context.Context, current *oauth2.Token) (*oauth2.Token, error) {
+		fetched++
+		return &oauth2.Token{AccessToken: "token", Expiry: time.Now().Add(time.Hour)}, nil
+	}
+	token, err := NewCachedTokenSource(cache, fetch).Token()
+	assert.Nil(t, err)
+	assert.Equal(t, "token", token.AccessToken)
+
+	// A new source, as in a new goft invocation, uses the cached token
+	source := NewCachedTokenSource(cache, fetch)
+	_, err = source.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, fetched)
+
+	_, err = source.Refresh()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, fetched)
+}
+
+func TestCachedTokenSourceWithExpiredToken(t *testing.T) {
+	cache := tempTokenCache(t)
+	assert.Nil(t, cache.Save(&oauth2.Token{AccessToken: "expired", RefreshToken: "refresh", Expiry: time.Now().Add(-time.Minute)}))
+	source := NewCachedTokenSource(cache, func(ctx context.Context, current *oauth2.Token) (*oauth2.Token, error) {
+		assert.Equal(t, "refresh", current.RefreshToken)
+		return &oauth2.Token{AccessToken: "fresh", Expiry: time.Now().Add(time.Hour)}, nil
+	})
+	token, err := source.Token()
+	assert.Nil(t, err)
+	assert.Equal(t, "fresh", token.AccessToken)
+
+	cached, _ := cache.Load()
+	assert.Equal(t, "fresh", cached.AccessToken)
+}
+
+func TestCachedTokenSourceWithFailure(t *testing.T) {
+	cache := tempTokenCache(t)
+	source := NewCachedTokenSource(cache, func(ctx context.Context, current *oauth2.Token) (*oauth2.Token, error) {
+		return nil, errors.New("invalid_client")
+	})
+	_, err := source.Token()
+	assert.NotNil(t, err)
+	_, statErr := os.Stat(cache.Path)
+	assert.True(t, os.IsNotExist(statErr))
+}
+
+func TestNewFromTokenSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+		_, _ = rw.Write([]byte(`{"login":"spoody"}`))
+	}))
+	defer server.Close()
+	source := NewCachedTokenSource(tempTokenCache(t), func(ctx context.Context, current *oauth2.Token) (*oauth2.Token, error) {
+		return &oauth2.Token{AccessToken: "token", Expiry: time.Now().Add(time.Hour)}, nil
+	})
+	ftAPI := NewFromTokenSource(context.Background(), server.URL, source, WithRateLimiter(nil))
+	user, err := ftAPI.GetUserByLogin("spoody")
+	assert.Nil(t, err)
+	assert.Equal(t, "spoody", user.Login)
+}
+
+func TestNewFromTokenSourceFetchesWithRequestContext(t *testing.T) {
+	source := NewCachedTokenSource(tempTokenCache(t), func(ctx context.Context, current *oauth2.Token) (*oauth2.Token, error) {
+		// An unreachable token endpoint
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	ftAPI := NewFromTokenSource(context.Background(), "http://127.0.0.1:0", source, WithRateLimiter(nil))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := ftAPI.GetUserByLoginContext(ctx, "spoody")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
@@ -1,32 +1,5 @@
 package ftapi
 
-import "time"
-
-type cursus struct {
-	ID int `json:"id,omitempty"`
-	CreatedAt time.Time `json:"created_at,omitempty"`
-	Name string `json:"name,omitempty"`
-	Slug string `json:"slug,omitempty"`
-}
-
-type skill struct {
-	ID int `json:"id,omitempty"`
-	Name string `json:"name,omitempty"`
-	Level float32 `json:"level,omitempty"`
-}
-
-type cursusUser struct {
-	ID int `json:"id,omitempty"`
-	Grade string `json:"grade,omitempty"`
-	Level float32 `json:"level,omitempty"`
-	Skills []*skill `json:"skills,omitempty"`
-	BlackholedAt *time.Time `json:"blackholed_at,omitempty"`
-	BeginAt *time.Time `json:"begin_at,omitempty"`
-	EndAt *time.Time `json:"end_at,omitempty"`
-	HasCoalition bool `json:"has_coalition,omitempty"`
-	Cursus *cursus `json:"cursus,omitempty"`
-}
-
 type campusUser struct {
 	ID int `json:"id,omitempty"`
 	UserID int `json:"user_id,omitempty"`
@@ -58,9 +31,10 @@ type User struct {
 	PoolYear string `json:"pool_year,omitempty"`
 	Campuses []*Campus `json:"campus,omitempty"`
 	CampusUsers []*campusUser `json:"campus_users,omitempty"`
-	Roles []*role `json:"role,omitempty"`
-	CursusUsers []*cursusUser `json:"cursus_user,omitempty"`
-	Password string
+	Roles []*role `json:"roles,omitempty"`
+	CursusUsers []*CursusUser `json:"cursus_users,omitempty"`
+	// Password is only sent by UpdateUser, it is never part of the outputs
+	Password string `json:"-"`
 	Wallet int `json:"wallet,omitempty"`
 }
 